@@ -0,0 +1,125 @@
+package BBCode
+
+import (
+	"strings"
+	"testing"
+)
+
+// renderTests holds BBCode like that in GetNewsForApp output, and what each
+// renderer should make of it.
+var renderTests = []struct {
+	name, in, html, markdown, text string
+}{
+	{"heading",
+		"[h1]Patch Notes[/h1]\nSome text.",
+		"<h1>Patch Notes</h1>\nSome text.",
+		"# Patch Notes\n\nSome text.\n",
+		"Patch Notes\n\nSome text.\n"},
+	{"list",
+		"[list]\n[*]First\n[*]Second [b]bold[/b]\n[/list]",
+		"<ul><li>First</li>\n<li>Second <strong>bold</strong></li>\n</ul>\n",
+		"- First\n- Second **bold**\n",
+		"• First\n• Second bold\n"},
+	{"numbered list",
+		"[olist]\n[*]One\n[*]Two\n[/olist]",
+		"<ol><li>One</li>\n<li>Two</li>\n</ol>\n",
+		"1. One\n2. Two\n",
+		"1. One\n2. Two\n"},
+	{"clan image",
+		"[img]{STEAM_CLAN_IMAGE}/3703047/abc.png[/img]",
+		`<img src="` + ClanImageURL + `/3703047/abc.png" alt="">`,
+		"![](" + ClanImageURL + "/3703047/abc.png)\n",
+		"\n"},
+	{"YouTube preview",
+		"[previewyoutube=dQw4w9WgXcQ;full][/previewyoutube]",
+		`<p><a class="bb-youtube" href="https://www.youtube.com/watch?v=dQw4w9WgXcQ"` +
+			` rel="nofollow noopener">https://www.youtube.com/watch?v=dQw4w9WgXcQ</a></p>` +
+			"\n",
+		"[YouTube video](https://www.youtube.com/watch?v=dQw4w9WgXcQ)\n",
+		"https://www.youtube.com/watch?v=dQw4w9WgXcQ\n"},
+	{"link with value",
+		"[url=https://example.com/a?b=1&c=2]Read [i]more[/i][/url]",
+		`<a href="https://example.com/a?b=1&amp;c=2" rel="nofollow noopener">` +
+			"Read <em>more</em></a>",
+		"[Read *more*](https://example.com/a?b=1&c=2)\n",
+		"Read more (https://example.com/a?b=1&c=2)\n"},
+	{"bare link",
+		"[url]https://example.com[/url]",
+		`<a href="https://example.com" rel="nofollow noopener">https://example.com</a>`,
+		"[https://example.com](https://example.com)\n",
+		"https://example.com\n"},
+	{"javascript link",
+		"[url=javascript:alert(1)]click[/url]",
+		"click",
+		"click\n",
+		"click\n"},
+	{"javascript image",
+		"[img]javascript:alert(1)[/img]",
+		"",
+		"\n",
+		"\n"},
+	{"unknown tag",
+		"[foo]bar[/foo] [Update 3]",
+		"[foo]bar[/foo] [Update 3]",
+		`\[foo\]bar\[/foo\] \[Update 3\]` + "\n",
+		"[foo]bar[/foo] [Update 3]\n"},
+	{"unclosed tag",
+		"[b]unclosed",
+		"[b]unclosed",
+		`\[b\]unclosed` + "\n",
+		"[b]unclosed\n"},
+	{"escaping",
+		`<script>alert('x') & "q"</script> *not* _md_`,
+		"&lt;script&gt;alert(&#39;x&#39;) &amp; &#34;q&#34;&lt;/script&gt; *not* _md_",
+		`\<script\>alert('x') & "q"\</script\> \*not\* \_md\_` + "\n",
+		`<script>alert('x') & "q"</script> *not* _md_` + "\n"},
+	{"blocks",
+		"[p]Para[/p][hr][/hr][quote=Gabe]Hi[/quote]",
+		"<p>Para</p>\n<hr>\n<blockquote><cite>Gabe</cite>\nHi</blockquote>\n",
+		"Para\n\n---\n\n> Gabe wrote:\n>\n> Hi\n",
+		"Para\n\n" + strings.Repeat("-", 40) + "\n\n> Gabe wrote:\n>\n> Hi\n"},
+	{"code",
+		"[code]x < y [b]not bold[/b][/code]",
+		"<pre><code>x &lt; y [b]not bold[/b]</code></pre>\n",
+		"```\nx < y [b]not bold[/b]\n```\n",
+		"    x < y [b]not bold[/b]\n"},
+}
+
+func TestRender(t *testing.T) {
+	for _, test := range renderTests {
+		if got := ToHTML(test.in); got != test.html {
+			t.Errorf("%s: ToHTML(%q)\n got %q\nwant %q", test.name, test.in, got, test.html)
+		}
+		if got := ToMarkdown(test.in); got != test.markdown {
+			t.Errorf("%s: ToMarkdown(%q)\n got %q\nwant %q",
+				test.name, test.in, got, test.markdown)
+		}
+		if got := ToText(test.in); got != test.text {
+			t.Errorf("%s: ToText(%q)\n got %q\nwant %q", test.name, test.in, got, test.text)
+		}
+	}
+}
+
+func TestParseUnclosedTagsIsText(t *testing.T) {
+	in := strings.Repeat("[b]x", 20000)
+	root := Parse(in)
+	if len(root.Children) != 1 || root.Children[0].Type != TextNode ||
+		root.Children[0].Text != in {
+		t.Errorf("Parse of %d unclosed tags gave %d nodes, want one text node",
+			20000, len(root.Children))
+	}
+}
+
+func TestParseLimitsNesting(t *testing.T) {
+	in := strings.Repeat("[b]", 1000) + "x" + strings.Repeat("[/b]", 1000)
+	depth := 0
+	for n := Parse(in); len(n.Children) > 0; n = n.Children[0] {
+		depth++
+	}
+	if depth > maxNesting+1 {
+		t.Errorf("Parse nested tags %d deep, want at most %d", depth, maxNesting+1)
+	}
+	if got := Parse(in).TextContent(); !strings.Contains(got, "x") {
+		t.Errorf("Parse lost the text inside deeply nested tags: %q", got)
+	}
+}
@@ -0,0 +1,21 @@
+// Package BBCode converts text in Steam’s dialect of BBCode, as found in news
+// items, workshop descriptions and event announcements, to HTML, Markdown or
+// plain text.
+//
+// Steam’s dialect has the usual inline tags ([b], [i], [u], [strike], [url],
+// [spoiler]), headings ([h1] to [h3]), lists ([list] or [olist] with [*] items),
+// [quote], [code], [noparse], [hr] and tables, plus Steam-specific tags such as
+// [previewyoutube=ID;full] and [video mp4=URL webm=URL poster=URL]. Image URLs
+// often start with the placeholder {STEAM_CLAN_IMAGE}, which Parse replaces
+// with ClanImageURL.
+//
+// Parse turns the text into a tree of Nodes. The HTML, Markdown and PlainText
+// methods of the root Node (or the ToHTML, ToMarkdown and ToText functions,
+// which do both steps) render the tree.
+//
+// Unknown tags, known tags without a matching closing tag, and tags nested
+// absurdly deep are kept as literal text, so text like “[Update 3]” survives
+// unchanged. The HTML output escapes all text and drops any URL whose scheme
+// is not http, https or mailto, so it is safe to embed in a page.
+//
+package BBCode // import "github.com/c12h/SteamAPI/BBCode"
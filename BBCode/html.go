@@ -0,0 +1,151 @@
+package BBCode
+
+import (
+	"html"
+	"strings"
+)
+
+// Function ToHTML converts BBCode text to HTML. See Node.HTML.
+func ToHTML(s string) string { return Parse(s).HTML() }
+
+// Method HTML renders the tree rooted at n as HTML. All text is escaped, URLs
+// which are not http, https or mailto ones are dropped, and links get
+// rel="nofollow noopener". Unknown tags are rendered as text.
+//
+// Spoilers become <span class="bb-spoiler">, and YouTube previews become links
+// with class "bb-youtube"; style them as you wish.
+//
+func (n *Node) HTML() string {
+	var sb strings.Builder
+	writeHTML(&sb, n)
+	return sb.String()
+}
+
+var simpleHTMLTags = map[string]string{
+	"b": "strong", "i": "em", "u": "u", "s": "s", "strike": "s",
+	"h1": "h1", "h2": "h2", "h3": "h3", "h4": "h4", "h5": "h5", "h6": "h6",
+	"p": "p", "list": "ul", "olist": "ol", "*": "li",
+	"table": "table", "tr": "tr", "th": "th", "td": "td",
+}
+
+func writeHTML(sb *strings.Builder, n *Node) {
+	if n.Type == TextNode {
+		writeHTMLText(sb, n.Text)
+		return
+	} else if n.Type == TagNode && !n.IsKnown() {
+		writeHTMLText(sb, n.Open)
+		writeHTMLChildren(sb, n)
+		writeHTMLText(sb, n.Close)
+		return
+	}
+
+	if elt, ok := simpleHTMLTags[n.Name]; ok {
+		sb.WriteString("<" + elt + ">")
+		writeHTMLChildren(sb, n)
+		sb.WriteString("</" + elt + ">")
+		if isBlock(n) {
+			sb.WriteByte('\n')
+		}
+		return
+	}
+	switch n.Name {
+	case "": // The root
+		writeHTMLChildren(sb, n)
+	case "hr":
+		sb.WriteString("<hr>\n")
+	case "br":
+		sb.WriteString("<br>\n")
+	case "spoiler":
+		sb.WriteString(`<span class="bb-spoiler">`)
+		writeHTMLChildren(sb, n)
+		sb.WriteString("</span>")
+	case "noparse":
+		writeHTMLChildren(sb, n)
+	case "code":
+		sb.WriteString("<pre><code>")
+		sb.WriteString(html.EscapeString(trimOneNewline(n.TextContent(), true)))
+		sb.WriteString("</code></pre>\n")
+	case "quote":
+		sb.WriteString("<blockquote>")
+		if author := quoteAuthor(n); author != "" {
+			sb.WriteString("<cite>" + html.EscapeString(author) + "</cite>\n")
+		}
+		writeHTMLChildren(sb, n)
+		sb.WriteString("</blockquote>\n")
+	case "url":
+		href := safeURL(linkTarget(n))
+		if href == "" {
+			writeHTMLChildren(sb, n)
+			break
+		}
+		sb.WriteString(`<a href="` + html.EscapeString(href) +
+			`" rel="nofollow noopener">`)
+		writeHTMLChildren(sb, n)
+		sb.WriteString("</a>")
+	case "img":
+		if src := safeURL(imageSource(n)); src != "" {
+			sb.WriteString(`<img src="` + html.EscapeString(src) + `" alt="">`)
+		}
+	case "emoticon":
+		writeHTMLText(sb, ":"+n.TextContent()+":")
+	case "previewyoutube":
+		if href := youTubeURL(n); href != "" {
+			sb.WriteString(`<p><a class="bb-youtube" href="` + href +
+				`" rel="nofollow noopener">` + href + "</a></p>\n")
+		}
+	case "video":
+		writeHTMLVideo(sb, n)
+	}
+}
+
+func writeHTMLChildren(sb *strings.Builder, n *Node) {
+	inStructure := n.Name == "list" || n.Name == "olist" ||
+		n.Name == "table" || n.Name == "tr"
+	for i, child := range n.Children {
+		if child.Type != TextNode {
+			writeHTML(sb, child)
+			continue
+		}
+		s := childText(n, i)
+		if inStructure && strings.TrimSpace(s) == "" {
+			continue
+		}
+		writeHTMLText(sb, s)
+	}
+}
+
+func writeHTMLText(sb *strings.Builder, s string) {
+	s = strings.Replace(s, "\r\n", "\n", -1)
+	sb.WriteString(strings.Replace(html.EscapeString(s), "\n", "<br>\n", -1))
+}
+
+func writeHTMLVideo(sb *strings.Builder, n *Node) {
+	var sources []string
+	for _, kind := range []string{"webm", "mp4"} {
+		if src := safeURL(n.Attrs[kind]); src != "" {
+			sources = append(sources, `<source src="`+html.EscapeString(src)+
+				`" type="video/`+kind+`">`)
+		}
+	}
+	if len(sources) == 0 {
+		return
+	}
+	sb.WriteString("<video controls")
+	if poster := safeURL(n.Attrs["poster"]); poster != "" {
+		sb.WriteString(` poster="` + html.EscapeString(poster) + `"`)
+	}
+	sb.WriteString(">" + strings.Join(sources, "") + "</video>\n")
+}
+
+// quoteAuthor returns the author of a [quote=AUTHOR] or [quote author=AUTHOR]
+// tag, if any. Steam sometimes appends ";ID" to the author’s name.
+func quoteAuthor(n *Node) string {
+	author := n.Value
+	if author == "" {
+		author = n.Attrs["author"]
+	}
+	if semi := strings.IndexByte(author, ';'); semi >= 0 {
+		author = author[:semi]
+	}
+	return author
+}
@@ -0,0 +1,406 @@
+package BBCode
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// ClanImageURL is the CDN URL which Steam substitutes for {STEAM_CLAN_IMAGE}.
+const ClanImageURL = "https://clan.cloudflare.steamstatic.com/images"
+
+/*================================= The Tree =================================*/
+
+// Type NodeType says what a Node represents.
+type NodeType int
+
+const (
+	RootNode NodeType = iota // The whole document
+	TextNode                 // Literal text
+	TagNode                  // A tag and its contents
+)
+
+// Type Node is one node of the tree built by Parse.
+//
+// For a TagNode, Name is the lower-cased tag name (such as "b", "url" or "*"),
+// Value is the text after any ‘=’ (as in [url=VALUE]), and Attrs holds any
+// other attributes (as in [video mp4=URL]). Open and Close are the tags as
+// written; Close is "" if the tag was closed implicitly, as [*] items are.
+//
+type Node struct {
+	Type     NodeType
+	Text     string // For TextNodes only
+	Name     string
+	Value    string
+	Attrs    map[string]string
+	Open     string
+	Close    string
+	Children []*Node
+}
+
+// Method IsKnown reports whether n is a tag this package knows how to render.
+func (n *Node) IsKnown() bool {
+	return n.Type == TagNode && knownTags[n.Name] != 0
+}
+
+// Method TextContent returns all the text within n, without any tags.
+func (n *Node) TextContent() string {
+	if n.Type == TextNode {
+		return n.Text
+	}
+	var sb strings.Builder
+	for _, child := range n.Children {
+		sb.WriteString(child.TextContent())
+	}
+	return sb.String()
+}
+
+type tagKind int
+
+const (
+	inlineTag tagKind = iota + 1
+	blockTag
+	voidTag  // Has no contents or closing tag, like [hr]
+	rawTag   // Contents are not parsed, like [code]
+	itemTag  // [*], closed by the next [*] or the end of the list
+	blockRaw // Both block and raw
+)
+
+var knownTags = map[string]tagKind{
+	"b": inlineTag, "i": inlineTag, "u": inlineTag, "s": inlineTag,
+	"strike": inlineTag, "spoiler": inlineTag, "url": inlineTag,
+	"img": inlineTag, "emoticon": inlineTag,
+	"h1": blockTag, "h2": blockTag, "h3": blockTag, "h4": blockTag,
+	"h5": blockTag, "h6": blockTag, "p": blockTag, "quote": blockTag,
+	"list": blockTag, "olist": blockTag, "table": blockTag, "tr": blockTag,
+	"th": blockTag, "td": blockTag, "previewyoutube": blockTag,
+	"video": blockTag, "code": blockRaw, "noparse": rawTag,
+	"hr": voidTag, "br": voidTag, "*": itemTag,
+}
+
+func isBlock(n *Node) bool {
+	if n.Type != TagNode {
+		return false
+	}
+	k := knownTags[n.Name]
+	return k == blockTag || k == itemTag || k == blockRaw || n.Name == "hr"
+}
+
+/*================================= Parsing ==================================*/
+
+// maxNesting is how deeply Parse lets tags nest. Real posts never come close,
+// and the renderers take time proportional to the depth times the length.
+const maxNesting = 50
+
+// Function Parse parses BBCode text into a tree, after replacing the
+// {STEAM_CLAN_IMAGE} placeholder. It never fails: anything it cannot make sense
+// of, including tags nested more than 50 deep, becomes text. It takes time
+// proportional to the length of s.
+//
+func Parse(s string) *Node {
+	s = strings.Replace(s, "{STEAM_CLAN_IMAGE}", ClanImageURL, -1)
+	closers := findClosers(s)
+	root := &Node{Type: RootNode}
+	stack := []*Node{root}
+	open := make(map[string]int) // How many of each tag are on the stack
+	popTo := func(k int) {
+		for _, node := range stack[k:] {
+			open[node.Name]--
+		}
+		stack = stack[:k]
+	}
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			top := stack[len(stack)-1]
+			top.Children = append(top.Children,
+				&Node{Type: TextNode, Text: text.String()})
+			text.Reset()
+		}
+	}
+
+	for i := 0; i < len(s); {
+		if s[i] != '[' {
+			j := strings.IndexByte(s[i:], '[')
+			if j < 0 {
+				j = len(s) - i
+			}
+			text.WriteString(s[i : i+j])
+			i += j
+			continue
+		}
+		tok, ok := scanTag(s[i:])
+		if !ok {
+			text.WriteByte('[')
+			i++
+			continue
+		}
+		kind := knownTags[tok.name]
+
+		if tok.closing {
+			k := 0
+			if open[tok.name] > 0 {
+				k = len(stack) - 1
+				for stack[k].Name != tok.name {
+					k--
+				}
+			}
+			if k > 0 {
+				flush()
+				stack[k].Close = tok.raw
+				popTo(k)
+			} else if kind != voidTag { // Ignore [/hr] and the like
+				text.WriteString(tok.raw)
+			}
+			i += len(tok.raw)
+			continue
+		}
+
+		after := i + len(tok.raw)
+		positions := closers[tok.name]
+		if kind != voidTag && kind != itemTag &&
+			(len(positions) == 0 || positions[len(positions)-1] < after) ||
+			len(stack) > maxNesting {
+			// Unclosed, too deep, or not a tag at all: keep it as text
+			text.WriteString(tok.raw)
+			i += len(tok.raw)
+			continue
+		}
+		flush()
+		if kind == itemTag && open["*"] > 0 {
+			// A new [*] ends the previous item of the same list
+			for k := len(stack) - 1; k > 0; k-- {
+				name := stack[k].Name
+				if name == "*" {
+					popTo(k)
+					break
+				} else if name == "list" || name == "olist" {
+					break
+				}
+			}
+		}
+		node := &Node{Type: TagNode, Name: tok.name, Value: tok.value,
+			Attrs: tok.attrs, Open: tok.raw}
+		top := stack[len(stack)-1]
+		top.Children = append(top.Children, node)
+		i += len(tok.raw)
+
+		switch kind {
+		case voidTag:
+		case rawTag, blockRaw:
+			end := positions[sort.SearchInts(positions, i)]
+			if end > i {
+				node.Children = []*Node{{Type: TextNode, Text: s[i:end]}}
+			}
+			i = end + len("[/]") + len(tok.name)
+			node.Close = s[end:i]
+		default:
+			stack = append(stack, node)
+			open[node.Name]++
+		}
+	}
+	flush()
+	return root
+}
+
+// findClosers returns the positions in s of the closing tags without spaces
+// (such as "[/b]", in any case), in order, keyed by lower-cased tag name.
+// Parse uses it to tell whether a tag is closed without searching the rest of
+// s for every tag, which would make Parse quadratic.
+func findClosers(s string) map[string][]int {
+	closers := make(map[string][]int)
+	for i := 0; i < len(s); {
+		j := strings.Index(s[i:], "[/")
+		if j < 0 {
+			break
+		}
+		i += j + 2
+		n := 0
+		for i+n < len(s) && n <= 20 && isNameByte(s[i+n]) {
+			n++
+		}
+		if n > 0 && n <= 20 && i+n < len(s) && s[i+n] == ']' {
+			name := strings.ToLower(s[i : i+n])
+			closers[name] = append(closers[name], i-2)
+		}
+	}
+	return closers
+}
+
+type tagToken struct {
+	raw     string
+	name    string
+	closing bool
+	value   string
+	attrs   map[string]string
+}
+
+const maxTagLength = 2000
+
+// scanTag tries to read a tag from the start of s, which starts with '['.
+func scanTag(s string) (tok tagToken, ok bool) {
+	end := strings.IndexAny(s[1:], "[]\n")
+	if end < 0 || end > maxTagLength || s[1+end] != ']' {
+		return tok, false
+	}
+	tok.raw = s[:end+2]
+	inner := s[1 : end+1]
+	if strings.HasPrefix(inner, "/") {
+		tok.closing = true
+		inner = inner[1:]
+	}
+	n := 0
+	for n < len(inner) && isNameByte(inner[n]) {
+		n++
+	}
+	if n == 0 || n > 20 || (strings.IndexByte(inner[:n], '*') >= 0 && n != 1) {
+		return tok, false
+	}
+	tok.name = strings.ToLower(inner[:n])
+	inner = inner[n:]
+	if tok.closing {
+		return tok, strings.TrimSpace(inner) == ""
+	}
+
+	if strings.HasPrefix(inner, "=") {
+		tok.value, inner, ok = scanValue(inner[1:])
+		if !ok {
+			return tok, false
+		}
+	}
+	for {
+		inner = strings.TrimLeft(inner, " ")
+		if inner == "" {
+			return tok, true
+		}
+		eq := strings.IndexByte(inner, '=')
+		if eq <= 0 || strings.IndexByte(inner[:eq], ' ') >= 0 {
+			return tok, false
+		}
+		key := strings.ToLower(inner[:eq])
+		var value string
+		value, inner, ok = scanValue(inner[eq+1:])
+		if !ok {
+			return tok, false
+		}
+		if tok.attrs == nil {
+			tok.attrs = make(map[string]string)
+		}
+		tok.attrs[key] = value
+	}
+}
+
+func isNameByte(b byte) bool {
+	return 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z' || '0' <= b && b <= '9' ||
+		b == '*'
+}
+
+// scanValue reads an attribute value, which may be in double quotes, and
+// returns it and the rest of s. Unquoted values end at a space, unless they
+// are the last thing in the tag, in which case they can contain spaces.
+func scanValue(s string) (value, rest string, ok bool) {
+	if strings.HasPrefix(s, `"`) {
+		end := strings.IndexByte(s[1:], '"')
+		if end < 0 {
+			return "", "", false
+		}
+		return s[1 : end+1], s[end+2:], true
+	}
+	if sp := strings.IndexByte(s, ' '); sp >= 0 && strings.IndexByte(s[sp:], '=') >= 0 {
+		return s[:sp], s[sp:], true
+	}
+	return s, "", true
+}
+
+/*================================= Helpers ==================================*/
+
+// safeURL returns u if it is an absolute http, https or mailto URL (or a
+// protocol-relative one, which it makes https), and "" otherwise.
+func safeURL(u string) string {
+	u = strings.TrimSpace(u)
+	if strings.HasPrefix(u, "//") {
+		u = "https:" + u
+	}
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return ""
+	}
+	switch strings.ToLower(parsed.Scheme) {
+	case "http", "https":
+		if parsed.Host == "" {
+			return ""
+		}
+		return u
+	case "mailto":
+		return u
+	}
+	return ""
+}
+
+// linkTarget returns the target of a [url] tag, which is either its value or
+// its contents.
+func linkTarget(n *Node) string {
+	if n.Value != "" {
+		return n.Value
+	}
+	return n.TextContent()
+}
+
+// imageSource returns the URL of an [img] tag, which can be in its contents, in
+// its value or in a src attribute.
+func imageSource(n *Node) string {
+	if src := n.Attrs["src"]; src != "" {
+		return src
+	} else if n.Value != "" {
+		return n.Value
+	}
+	return n.TextContent()
+}
+
+// youTubeURL returns the URL of the video for a [previewyoutube=ID;full] tag,
+// or "" if the ID looks wrong.
+func youTubeURL(n *Node) string {
+	id := n.Value
+	if semi := strings.IndexByte(id, ';'); semi >= 0 {
+		id = id[:semi]
+	}
+	if id == "" {
+		return ""
+	}
+	for i := 0; i < len(id); i++ {
+		b := id[i]
+		if !('a' <= b && b <= 'z' || 'A' <= b && b <= 'Z' ||
+			'0' <= b && b <= '9' || b == '-' || b == '_') {
+			return ""
+		}
+	}
+	return "https://www.youtube.com/watch?v=" + id
+}
+
+// childText returns the text of n.Children[i], which must be a TextNode,
+// without a newline next to a block-level tag (or the start or end of a block),
+// since those newlines only serve to lay out the BBCode.
+func childText(n *Node, i int) string {
+	s := n.Children[i].Text
+	if i == 0 && n.Type != RootNode || i > 0 && isBlock(n.Children[i-1]) {
+		s = trimOneNewline(s, true)
+	}
+	last := len(n.Children) - 1
+	if i == last && n.Type != RootNode || i < last && isBlock(n.Children[i+1]) {
+		s = trimOneNewline(s, false)
+	}
+	return s
+}
+
+func trimOneNewline(s string, atStart bool) string {
+	if atStart {
+		if strings.HasPrefix(s, "\r\n") {
+			return s[2:]
+		}
+		return strings.TrimPrefix(s, "\n")
+	}
+	if strings.HasSuffix(s, "\r\n") {
+		return s[:len(s)-2]
+	}
+	return strings.TrimSuffix(s, "\n")
+}
@@ -0,0 +1,322 @@
+package BBCode
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Function ToMarkdown converts BBCode text to Markdown. See Node.Markdown.
+func ToMarkdown(s string) string { return Parse(s).Markdown() }
+
+// Function ToText converts BBCode text to plain text. See Node.PlainText.
+func ToText(s string) string { return Parse(s).PlainText() }
+
+// Method Markdown renders the tree rooted at n as (GitHub-flavoured) Markdown.
+// Text is escaped so it cannot be mistaken for Markdown, and line breaks in the
+// BBCode become hard line breaks. Markdown has no underlining or spoilers, so
+// [u] and [spoiler] tags are dropped (but not their contents). Unsafe URLs are
+// dropped, as for HTML.
+//
+func (n *Node) Markdown() string {
+	return tidyLines((&textRenderer{markdown: true}).render(n))
+}
+
+// Method PlainText renders the tree rooted at n as plain text, with list items
+// marked by bullets or numbers, quotes marked by “> ”, and link targets given
+// in parentheses after the link text. Images are omitted.
+//
+func (n *Node) PlainText() string {
+	return tidyLines((&textRenderer{}).render(n))
+}
+
+type textRenderer struct {
+	markdown bool
+}
+
+func block(s string) string { return "\n\n" + s + "\n\n" }
+
+func (r *textRenderer) render(n *Node) string {
+	if n.Type == TextNode {
+		return r.text(n.Text)
+	} else if n.Type == TagNode && !n.IsKnown() {
+		return r.text(n.Open) + r.children(n) + r.text(n.Close)
+	}
+
+	switch n.Name {
+	case "": // The root
+		return r.children(n)
+	case "b":
+		return r.wrap("**", r.children(n))
+	case "i":
+		return r.wrap("*", r.children(n))
+	case "s", "strike":
+		return r.wrap("~~", r.children(n))
+	case "u", "spoiler", "p":
+		if n.Name == "p" {
+			return block(r.children(n))
+		}
+		return r.children(n)
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		heading := oneLine(r.children(n))
+		if r.markdown {
+			level, _ := strconv.Atoi(n.Name[1:])
+			heading = strings.Repeat("#", level) + " " + heading
+		}
+		return block(heading)
+	case "list", "olist":
+		return block(r.list(n))
+	case "*":
+		return block(r.item(n, r.bullet()))
+	case "quote":
+		return block(r.quote(n))
+	case "code":
+		return block(r.code(n.TextContent()))
+	case "noparse":
+		return r.text(n.TextContent())
+	case "hr":
+		if r.markdown {
+			return block("---")
+		}
+		return block(strings.Repeat("-", 40))
+	case "br":
+		return r.text("\n")
+	case "url":
+		return r.link(n)
+	case "img":
+		if src := safeURL(imageSource(n)); src != "" && r.markdown {
+			return "![](" + escapeLinkURL(src) + ")"
+		}
+		return ""
+	case "emoticon":
+		return r.text(":" + n.TextContent() + ":")
+	case "previewyoutube":
+		return block(r.bareLink("YouTube video", youTubeURL(n)))
+	case "video":
+		src := safeURL(n.Attrs["webm"])
+		if src == "" {
+			src = safeURL(n.Attrs["mp4"])
+		}
+		return block(r.bareLink("Video", src))
+	case "table":
+		return block(r.table(n))
+	case "tr", "th", "td": // Outside a table
+		return block(r.children(n))
+	}
+	return r.children(n)
+}
+
+func (r *textRenderer) children(n *Node) string {
+	var sb strings.Builder
+	for i, child := range n.Children {
+		if child.Type == TextNode {
+			sb.WriteString(r.text(childText(n, i)))
+		} else {
+			sb.WriteString(r.render(child))
+		}
+	}
+	return sb.String()
+}
+
+var markdownSpecials = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", `*`, `\*`, `_`, `\_`, `[`, `\[`, `]`, `\]`,
+	`<`, `\<`, `>`, `\>`, `#`, `\#`, `|`, `\|`, `~`, `\~`)
+
+func (r *textRenderer) text(s string) string {
+	s = strings.Replace(s, "\r\n", "\n", -1)
+	if !r.markdown {
+		return s
+	}
+	return strings.Replace(markdownSpecials.Replace(s), "\n", "  \n", -1)
+}
+
+func (r *textRenderer) wrap(marker, s string) string {
+	if !r.markdown || strings.TrimSpace(s) == "" {
+		return s
+	}
+	// Markdown needs the markers right next to the text
+	trimmed := strings.TrimSpace(s)
+	lead := s[:strings.Index(s, trimmed)]
+	trail := s[len(lead)+len(trimmed):]
+	return lead + marker + trimmed + marker + trail
+}
+
+func (r *textRenderer) bullet() string {
+	if r.markdown {
+		return "- "
+	}
+	return "• "
+}
+
+func (r *textRenderer) list(n *Node) string {
+	var items []string
+	number := 0
+	for i, child := range n.Children {
+		var s string
+		if child.Type == TextNode {
+			s = strings.TrimSpace(r.text(childText(n, i)))
+			if s == "" {
+				continue
+			}
+		} else if child.Name != "*" {
+			s = strings.TrimSpace(r.render(child))
+		} else {
+			number++
+			marker := r.bullet()
+			if n.Name == "olist" {
+				marker = strconv.Itoa(number) + ". "
+			}
+			s = r.item(child, marker)
+		}
+		items = append(items, s)
+	}
+	return strings.Join(items, "\n")
+}
+
+func (r *textRenderer) item(n *Node, marker string) string {
+	s := strings.TrimSpace(collapseBlankLines(r.children(n)))
+	return marker + indentLines(s, strings.Repeat(" ", len([]rune(marker))))
+}
+
+func (r *textRenderer) quote(n *Node) string {
+	s := strings.TrimSpace(collapseBlankLines(r.children(n)))
+	if author := quoteAuthor(n); author != "" {
+		s = r.text(author) + " wrote:\n\n" + s
+	}
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = ">"
+		} else {
+			lines[i] = "> " + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (r *textRenderer) code(s string) string {
+	s = strings.Trim(strings.Replace(s, "\r\n", "\n", -1), "\n")
+	if !r.markdown {
+		return indentLines("    "+s, "    ")
+	}
+	fence := "```"
+	if strings.Contains(s, fence) {
+		fence = "~~~~"
+	}
+	return fence + "\n" + s + "\n" + fence
+}
+
+func (r *textRenderer) link(n *Node) string {
+	label := r.children(n)
+	target := safeURL(linkTarget(n))
+	if target == "" {
+		return label
+	} else if r.markdown {
+		if strings.TrimSpace(label) == "" {
+			label = r.text(target)
+		}
+		return "[" + oneLine(label) + "](" + escapeLinkURL(target) + ")"
+	} else if label == "" || label == target {
+		return target
+	}
+	return label + " (" + target + ")"
+}
+
+func (r *textRenderer) bareLink(label, target string) string {
+	if target == "" {
+		return ""
+	} else if r.markdown {
+		return "[" + label + "](" + escapeLinkURL(target) + ")"
+	}
+	return target
+}
+
+func (r *textRenderer) table(n *Node) string {
+	var rows [][]string
+	for _, row := range n.Children {
+		if row.Type != TagNode || row.Name != "tr" {
+			continue
+		}
+		var cells []string
+		for _, cell := range row.Children {
+			if cell.Type == TagNode && (cell.Name == "th" || cell.Name == "td") {
+				cells = append(cells, oneLine(r.children(cell)))
+			}
+		}
+		rows = append(rows, cells)
+	}
+	if len(rows) == 0 {
+		return ""
+	}
+
+	var lines []string
+	if !r.markdown {
+		for _, cells := range rows {
+			lines = append(lines, strings.Join(cells, "\t"))
+		}
+		return strings.Join(lines, "\n")
+	}
+	width := 0
+	for _, cells := range rows {
+		if len(cells) > width {
+			width = len(cells)
+		}
+	}
+	for i, cells := range rows {
+		for len(cells) < width {
+			cells = append(cells, "")
+		}
+		lines = append(lines, "| "+strings.Join(cells, " | ")+" |")
+		if i == 0 {
+			lines = append(lines,
+				"|"+strings.Repeat(" --- |", width))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+/*================================= Helpers ==================================*/
+
+var (
+	regexpSpaces      = regexp.MustCompile(`\s+`)
+	regexpBlankLines  = regexp.MustCompile(`\n{3,}`)
+	regexpHardBreaks  = regexp.MustCompile(`  \n`)
+	linkURLReplacer   = strings.NewReplacer("(", "%28", ")", "%29", " ", "%20")
+	regexpEmptyBreaks = regexp.MustCompile(`(?m)^[ \t]+$`)
+)
+
+// oneLine squashes s onto one line, as Markdown headings and table cells need.
+func oneLine(s string) string {
+	s = regexpHardBreaks.ReplaceAllString(s, " ")
+	return strings.TrimSpace(regexpSpaces.ReplaceAllString(s, " "))
+}
+
+func collapseBlankLines(s string) string {
+	return regexpBlankLines.ReplaceAllString(s, "\n\n")
+}
+
+func indentLines(s, indent string) string {
+	lines := strings.Split(s, "\n")
+	for i := 1; i < len(lines); i++ {
+		if lines[i] != "" {
+			lines[i] = indent + lines[i]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func escapeLinkURL(u string) string { return linkURLReplacer.Replace(u) }
+
+// tidyLines removes blank lines at the start and end of s, runs of more than
+// one blank line, and hard line breaks which would come before a blank line.
+func tidyLines(s string) string {
+	s = regexpEmptyBreaks.ReplaceAllString(s, "")
+	lines := strings.Split(s, "\n")
+	for i := range lines {
+		if i+1 == len(lines) || lines[i+1] == "" {
+			lines[i] = strings.TrimSuffix(lines[i], "  ")
+		}
+	}
+	s = collapseBlankLines(strings.Join(lines, "\n"))
+	return strings.Trim(s, "\n") + "\n"
+}
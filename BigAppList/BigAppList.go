@@ -1,6 +1,7 @@
 package BigAppList
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"os"
@@ -63,8 +64,15 @@ var nullItem = NameAndNumber{}
 // current version of the list from Steam, caches it and returns it.
 //
 func FromCache() (*AppList, error) {
+	return FromCacheContext(context.Background())
+}
+
+// Function bigappslist.FromCacheContext() is like FromCache, but uses ctx to
+// limit any download; see FromCacheOrWebContext.
+//
+func FromCacheContext(ctx context.Context) (*AppList, error) {
 	const LongLongAgo = uint32(24 * 365 * 1000) // 1000 years should be enough
-	return FromCacheOrWeb(LongLongAgo)
+	return FromCacheOrWebContext(ctx, LongLongAgo)
 }
 
 // Function bigappslist.FromCacheOrWeb(N) returns the latest version of Steam's
@@ -77,6 +85,16 @@ func FromCache() (*AppList, error) {
 // or even 7*24 might be kinder to some users.
 //
 func FromCacheOrWeb(maxAgeHours uint32) (*AppList, error) {
+	return FromCacheOrWebContext(context.Background(), maxAgeHours)
+}
+
+// Function bigappslist.FromCacheOrWebContext(ctx, N) is like FromCacheOrWeb(N),
+// but stops downloading and parsing the list if ctx is cancelled or times out.
+// The resulting *WebError or *ReadError wraps ctx.Err(), so callers can test
+// for that with errors.Is.
+//
+func FromCacheOrWebContext(ctx context.Context, maxAgeHours uint32,
+) (*AppList, error) {
 	steamAPI.EnsureDirExists(ourCacheDir)
 	dh, err := os.Open(ourCacheDir)
 	if err != nil {
@@ -102,7 +120,7 @@ func FromCacheOrWeb(maxAgeHours uint32) (*AppList, error) {
 		}
 	}
 	if newestFile == nil || latestTime < cutoff {
-		return fetchAndCache()
+		return fetchAndCache(ctx)
 	}
 	path := filepath.Join(ourCacheDir, newestFile.Name())
 	al, err := FromTerseFile(path)
@@ -132,9 +150,17 @@ func FromJSONFile(path string) (*AppList, error) {
 	return FromJSON(fh, path, true)
 }
 
-func fetchAndCache() (*AppList, error) {
-	resp, err := http.Get(URL)
+func fetchAndCache(ctx context.Context) (*AppList, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, URL, nil)
+	if err != nil {
+		return nil, &WebError{Action: "build request for", URL: URL,
+			BaseError: err}
+	}
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
 		return nil, &WebError{Action: "GET", URL: URL, BaseError: err}
 	}
 	defer resp.Body.Close()
@@ -145,7 +171,7 @@ func fetchAndCache() (*AppList, error) {
 
 	unixTime := time.Now().Unix()
 
-	al, err := FromJSON(resp.Body, "Steam web API", false)
+	al, err := FromJSONContext(ctx, resp.Body, "Steam web API", false)
 	if err != nil {
 		return nil, err
 	}
@@ -250,6 +276,28 @@ func (al *AppList) FindNumberForNameUC(targetName string) (int, SteamAppID) {
 	return i, appID
 }
 
+// Method FillGameNames sets the Name of each game in lib which has none to the
+// name that the AppList has for that game (if any), and returns how many names
+// it filled in.
+//
+// This lets callers of steamAPI.GetOwnedGames skip include_appinfo, or fill in
+// names Steam left out.
+//
+func (al *AppList) FillGameNames(lib steamAPI.GameLibrary) int {
+	filled := 0
+	for appID, game := range lib {
+		if game.Name != "" {
+			continue
+		}
+		if _, name := al.FindNameForNumber(SteamAppID(appID)); name != "" {
+			game.Name = name
+			lib[appID] = game
+			filled++
+		}
+	}
+	return filled
+}
+
 /*============================= Filesystem Paths =============================*/
 
 const ourDirName = "BigAppLists"
@@ -341,7 +389,7 @@ func (e *WebError) Error() string {
 			e.Action, e.URL, e.BaseError)
 	} else {
 		return fmt.Sprintf("cannot %s %q: HTTP status %d (%s)",
-			e.BaseError, e.URL, e.StatusCode, e.StatusText)
+			e.Action, e.URL, e.StatusCode, e.StatusText)
 	}
 }
 
@@ -14,7 +14,8 @@
 //
 // This package provides two functions to get AppList structs.  Programs which
 // don't need up-to-date information can call LatestCached(). To get the big app
-// list as of at most n hours ago, use FromCacheOrWeb(n).
+// list as of at most n hours ago, use FromCacheOrWeb(n). Their Context variants,
+// FromCacheContext and FromCacheOrWebContext, let callers abandon a slow download.
 //
 // Some names contain UTF8 sequences for codepoints U+0092 and U+0099, which are
 // control characters, but represent "’" (U+2019 and "™" (U+2122) respectively in
@@ -3,6 +3,7 @@ package BigAppList
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -16,6 +17,13 @@ import (
 // FromJSON returns an AppList it creates by parsing JSON text from an io.Reader,
 // or an error, but not both.
 func FromJSON(r io.Reader, source string, isFile bool) (*AppList, error) {
+	return FromJSONContext(context.Background(), r, source, isFile)
+}
+
+// FromJSONContext is like FromJSON, but gives up part-way through if ctx is
+// cancelled or times out, returning a *ReadError which wraps ctx.Err().
+func FromJSONContext(ctx context.Context, r io.Reader, source string, isFile bool,
+) (*AppList, error) {
 	const (
 		formatStart   = `{"applist":{"apps":[{"appid":%d,"name":%q}`
 		safePeekStart = len(`{"applist":{"apps":[{"appid":1,"name":"`)
@@ -31,7 +39,10 @@ func FromJSON(r io.Reader, source string, isFile bool) (*AppList, error) {
 
 	s := peek(bufReader, safePeekStart)
 	n, err := fmt.Fscanf(bufReader, formatStart, &number, &name)
-	if n < 2 {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, &ReadError{AtStart: true, BaseError: ctxErr,
+			Source: source, IsFile: isFile}
+	} else if n < 2 {
 		s = append(s, "…"...)
 		logBug(s,
 			"scanf() of", source, isFile,
@@ -46,12 +57,19 @@ func FromJSON(r io.Reader, source string, isFile bool) (*AppList, error) {
 		maybeInsert(number, name, al, source, isFile)
 	}
 	for {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, &ReadError{BaseError: ctxErr,
+				Source: source, IsFile: isFile}
+		}
 		s = peek(bufReader, safePeekLater)
 		if len(s) == 3 && s[0] == ']' && s[1] == '}' && s[2] == '}' {
 			break
 		}
 		n, err := fmt.Fscanf(bufReader, formatLater, &number, &name)
-		if n < 2 {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, &ReadError{BaseError: ctxErr,
+				Source: source, IsFile: isFile}
+		} else if n < 2 {
 			logBug(s,
 				"scanf() of", source, isFile,
 				" with format %q → %d, %q\n", formatLater, n, err)
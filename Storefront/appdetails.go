@@ -0,0 +1,157 @@
+package Storefront
+
+// This file provides /api/appdetails, which describes one app as its store page
+// does.
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/url"
+
+	steamAPI "github.com/c12h/SteamAPI"
+)
+
+// Type AppDetails holds what the store says about an app. Descriptions and
+// requirements are HTML.
+type AppDetails struct {
+	Type                string                 `json:"type"` // "game", "dlc", "demo", ...
+	Name                string                 `json:"name"`
+	AppID               steamAPI.SteamItemID   `json:"steam_appid"`
+	IsFree              bool                   `json:"is_free"`
+	DetailedDescription string                 `json:"detailed_description"`
+	AboutTheGame        string                 `json:"about_the_game"`
+	ShortDescription    string                 `json:"short_description"`
+	SupportedLanguages  string                 `json:"supported_languages"`
+	HeaderImage         string                 `json:"header_image"`
+	Website             string                 `json:"website"`
+	Developers          []string               `json:"developers"`
+	Publishers          []string               `json:"publishers"`
+	PriceOverview       *PriceOverview         `json:"price_overview"` // nil if free or unpriced
+	Packages            []steamAPI.SteamItemID `json:"packages"`       // Subs containing the app
+	PackageGroups       []PackageGroup         `json:"package_groups"`
+	Platforms           Platforms              `json:"platforms"`
+	Categories          []Category             `json:"categories"`
+	Genres              []Genre                `json:"genres"`
+	ReleaseDate         ReleaseDate            `json:"release_date"`
+	PCRequirements      Requirements           `json:"pc_requirements"`
+	MacRequirements     Requirements           `json:"mac_requirements"`
+	LinuxRequirements   Requirements           `json:"linux_requirements"`
+	Screenshots         []Screenshot           `json:"screenshots"`
+}
+
+// Method PackageItems returns the subs which contain the app, as StoreItems.
+func (ad *AppDetails) PackageItems() []steamAPI.StoreItem {
+	items := make([]steamAPI.StoreItem, len(ad.Packages))
+	for i, id := range ad.Packages {
+		items[i] = steamAPI.SubItem(id)
+	}
+	return items
+}
+
+// Type PriceOverview holds an item's price in one country. Prices are in the
+// smallest unit of the currency, such as cents.
+type PriceOverview struct {
+	Currency         string `json:"currency"` // Like "USD"
+	Initial          int    `json:"initial"`  // Before any discount
+	Final            int    `json:"final"`    // After any discount
+	DiscountPercent  int    `json:"discount_percent"`
+	InitialFormatted string `json:"initial_formatted"` // "" if no discount
+	FinalFormatted   string `json:"final_formatted"`   // Like "$9.99"
+}
+
+// Type Platforms says which operating systems an app runs on.
+type Platforms struct {
+	Windows bool `json:"windows"`
+	Mac     bool `json:"mac"`
+	Linux   bool `json:"linux"`
+}
+
+// Type Category is a store category, such as "Single-player".
+type Category struct {
+	ID          int    `json:"id"`
+	Description string `json:"description"`
+}
+
+// Type Genre is a store genre, such as "Action". (Steam gives genre IDs as
+// strings.)
+type Genre struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+}
+
+// Type ReleaseDate holds an app's release date as the store shows it, which is
+// free text in the requested language, such as "10 Oct, 2007" or "Q3 2025".
+type ReleaseDate struct {
+	ComingSoon bool   `json:"coming_soon"`
+	Date       string `json:"date"`
+}
+
+// Type Requirements holds an app's system requirements for one platform.
+type Requirements struct {
+	Minimum     string `json:"minimum"`
+	Recommended string `json:"recommended"`
+}
+
+// Method UnmarshalJSON copes with the store giving an empty array, rather than
+// an object, when there are no requirements.
+func (r *Requirements) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		*r = Requirements{}
+		return nil
+	}
+	type plain Requirements
+	return json.Unmarshal(data, (*plain)(r))
+}
+
+// Type Screenshot holds the URLs of one screenshot.
+type Screenshot struct {
+	ID            int    `json:"id"`
+	PathThumbnail string `json:"path_thumbnail"`
+	PathFull      string `json:"path_full"`
+}
+
+// appDetailsEntry is the store's answer for one app, which is also what the
+// cache holds.
+type appDetailsEntry struct {
+	Success bool        `json:"success"`
+	Data    *AppDetails `json:"data,omitempty"`
+}
+
+// Function GetAppDetails calls DefaultClient.GetAppDetails.
+func GetAppDetails(ctx context.Context, app steamAPI.SteamItemID, opts *Options,
+) (*AppDetails, error) {
+	return DefaultClient.GetAppDetails(ctx, app, opts)
+}
+
+// Method GetAppDetails returns the store's details of an app, for the country
+// and language in opts (which can be nil), using a cached answer if there is a
+// fresh one.
+//
+// If the store has no details for the app, perhaps because it is not sold in
+// that country, GetAppDetails returns a *NotAvailableError. Such answers are
+// cached too.
+//
+func (c *Client) GetAppDetails(ctx context.Context, app steamAPI.SteamItemID,
+	opts *Options,
+) (*AppDetails, error) {
+	path := c.cachePath("appdetails", app, opts)
+	var entry appDetailsEntry
+	if !c.readCache(path, &entry) {
+		var resp map[string]appDetailsEntry
+		err := c.getJSON(ctx, &resp, "app details", "app "+app.String(),
+			"/api/appdetails/", url.Values{"appids": {app.String()}}, opts)
+		if err != nil {
+			return nil, err
+		}
+		entry = resp[app.String()]
+		if entry.Success && entry.Data == nil {
+			entry.Success = false
+		}
+		c.writeCache(path, &entry)
+	}
+	if !entry.Success {
+		return nil, &NotAvailableError{What: "app", ID: app.String(), CC: opts.cc()}
+	}
+	return entry.Data, nil
+}
@@ -0,0 +1,337 @@
+package Storefront
+
+// This file provides /appreviews/<appid>?json=1, which returns the user
+// reviews of an app a page at a time, and a summary of them.
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	steamAPI "github.com/c12h/SteamAPI"
+)
+
+// Type Review holds one user review. Playtimes are in minutes.
+type Review struct {
+	RecommendationID string `json:"recommendationid"`
+	Author           struct {
+		SteamID              steamAPI.SteamID `json:"steamid"`
+		NumGamesOwned        int              `json:"num_games_owned"`
+		NumReviews           int              `json:"num_reviews"`
+		PlaytimeForever      int              `json:"playtime_forever"`
+		PlaytimeLastTwoWeeks int              `json:"playtime_last_two_weeks"`
+		PlaytimeAtReview     int              `json:"playtime_at_review"`
+		LastPlayed           int64            `json:"last_played"` // Unix time
+	} `json:"author"`
+	Language                 string  `json:"language"`
+	Text                     string  `json:"review"`
+	TimestampCreated         int64   `json:"timestamp_created"` // Unix time
+	TimestampUpdated         int64   `json:"timestamp_updated"` // Unix time
+	VotedUp                  bool    `json:"voted_up"`          // Whether it recommends the app
+	VotesUp                  int     `json:"votes_up"`
+	VotesFunny               int     `json:"votes_funny"`
+	WeightedVoteScore        float64 `json:"weighted_vote_score"`
+	CommentCount             int     `json:"comment_count"`
+	SteamPurchase            bool    `json:"steam_purchase"`
+	ReceivedForFree          bool    `json:"received_for_free"`
+	WrittenDuringEarlyAccess bool    `json:"written_during_early_access"`
+}
+
+// Method UnmarshalJSON copes with Steam giving weighted_vote_score as a
+// string or as a number.
+func (r *Review) UnmarshalJSON(data []byte) error {
+	type plain Review
+	aux := struct {
+		*plain
+		WeightedVoteScore looseFloat `json:"weighted_vote_score"`
+	}{plain: (*plain)(r)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	r.WeightedVoteScore = float64(aux.WeightedVoteScore)
+	return nil
+}
+
+// Method Created returns the time the review was written.
+func (r *Review) Created() time.Time { return time.Unix(r.TimestampCreated, 0) }
+
+// Type ReviewQuerySummary holds the totals Steam reports with the first page
+// of reviews. They cover all the reviews matching the query, not just those
+// returned.
+type ReviewQuerySummary struct {
+	ReviewScore     int    `json:"review_score"`      // From 0 to 9
+	ReviewScoreDesc string `json:"review_score_desc"` // Like "Very Positive"
+	TotalPositive   int    `json:"total_positive"`
+	TotalNegative   int    `json:"total_negative"`
+	TotalReviews    int    `json:"total_reviews"`
+}
+
+// Type ReviewOptions holds the optional parameters for Reviews. See
+// https://partner.steamgames.com/doc/store/getreviews for the values allowed.
+type ReviewOptions struct {
+	Filter       string // "recent", "updated" or "all" (the default)
+	Language     string // A language name, or "all" (the default)
+	ReviewType   string // "all" (the default), "positive" or "negative"
+	PurchaseType string // "all" (the default), "steam" or "non_steam_purchase"
+	DayRange     int    // For Filter "all": only reviews from the last N days
+	PageSize     int    // Reviews per request, up to 100 (the default)
+	MaxReviews   int    // Stop after this many reviews; 0 means no limit
+}
+
+const maxReviewsPerPage = 100
+
+// Type ReviewIterator streams the reviews of an app, fetching one page at a
+// time as needed. Use it like this:
+//	it := client.Reviews(ctx, app, nil)
+//	for it.Next() {
+//		review := it.Review()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+//
+type ReviewIterator struct {
+	ctx     context.Context
+	client  *Client
+	app     steamAPI.SteamItemID
+	params  url.Values
+	max     int
+	page    []Review
+	index   int
+	count   int
+	cursor  string
+	seen    map[string]bool
+	summary *ReviewQuerySummary
+	done    bool
+	err     error
+}
+
+// Function Reviews calls DefaultClient.Reviews.
+func Reviews(ctx context.Context, app steamAPI.SteamItemID, opts *ReviewOptions,
+) *ReviewIterator {
+	return DefaultClient.Reviews(ctx, app, opts)
+}
+
+// Method Reviews returns an iterator over the reviews of an app which match
+// opts (which can be nil). It does not fetch anything until Next is called,
+// and only holds one page of reviews at a time. Reviews are not cached.
+//
+// Steam pages through reviews with an opaque cursor. The iterator stops when
+// Steam returns an empty page or a cursor it has seen before (which Steam does
+// at the end of the reviews), or after opts.MaxReviews reviews.
+//
+func (c *Client) Reviews(ctx context.Context, app steamAPI.SteamItemID,
+	opts *ReviewOptions,
+) *ReviewIterator {
+	if opts == nil {
+		opts = &ReviewOptions{}
+	}
+	pageSize := opts.PageSize
+	if pageSize <= 0 || pageSize > maxReviewsPerPage {
+		pageSize = maxReviewsPerPage
+	}
+	params := url.Values{"json": {"1"}, "num_per_page": {strconv.Itoa(pageSize)}}
+	setIf := func(name, value string) {
+		if value != "" {
+			params.Set(name, value)
+		}
+	}
+	setIf("filter", opts.Filter)
+	setIf("language", opts.Language)
+	setIf("review_type", opts.ReviewType)
+	setIf("purchase_type", opts.PurchaseType)
+	if opts.DayRange > 0 {
+		params.Set("day_range", strconv.Itoa(opts.DayRange))
+	}
+	return &ReviewIterator{ctx: ctx, client: c, app: app, params: params,
+		max: opts.MaxReviews, cursor: "*", seen: make(map[string]bool)}
+}
+
+// Method Next advances to the next review, fetching another page if needed,
+// and reports whether there is one. After it returns false, see Err.
+func (it *ReviewIterator) Next() bool {
+	if it.max > 0 && it.count >= it.max {
+		it.done = true
+	}
+	for !it.done && it.index+1 >= len(it.page) {
+		it.fetch()
+	}
+	if it.done && it.index+1 >= len(it.page) {
+		it.page = nil
+		return false
+	}
+	it.index++
+	it.count++
+	return true
+}
+
+// Method Review returns the current review. It is only valid after a call to
+// Next returned true, and until the next call to Next.
+func (it *ReviewIterator) Review() *Review { return &it.page[it.index] }
+
+// Method Err returns the error which stopped the iteration, if any.
+func (it *ReviewIterator) Err() error { return it.err }
+
+// Method Summary returns the totals Steam reported with the first page, or nil
+// if no page has been fetched yet.
+func (it *ReviewIterator) Summary() *ReviewQuerySummary { return it.summary }
+
+func (it *ReviewIterator) fetch() {
+	it.seen[it.cursor] = true
+	it.params.Set("cursor", it.cursor)
+	var resp struct {
+		Success      int                `json:"success"`
+		QuerySummary ReviewQuerySummary `json:"query_summary"`
+		Reviews      []Review           `json:"reviews"`
+		Cursor       string             `json:"cursor"`
+	}
+	who := "app " + it.app.String()
+	err := it.client.getJSON(it.ctx, &resp, "reviews", who,
+		"/appreviews/"+it.app.String(), it.params, nil)
+	if err != nil {
+		it.err, it.done = err, true
+		return
+	} else if resp.Success != 1 {
+		it.err = &NotAvailableError{What: "reviews of app", ID: it.app.String()}
+		it.done = true
+		return
+	}
+	if it.summary == nil {
+		it.summary = &resp.QuerySummary
+	}
+	it.page, it.index = resp.Reviews, -1
+	if it.max > 0 && len(it.page) > it.max-it.count {
+		it.page = it.page[:it.max-it.count]
+	}
+	if len(resp.Reviews) == 0 || resp.Cursor == "" || it.seen[resp.Cursor] {
+		it.done = true
+	}
+	it.cursor = resp.Cursor
+}
+
+/*================================ Statistics ================================*/
+
+// PlaytimeBucketHours are the upper bounds, in hours, of the playtime buckets
+// used by ReviewStats. The last bucket has no upper bound.
+var PlaytimeBucketHours = []float64{1, 5, 10, 20, 50, 100, 500}
+
+// Type ReviewStats accumulates statistics about reviews, without keeping the
+// reviews themselves.
+type ReviewStats struct {
+	Positive int
+	Negative int
+	// Reviews by playtime at the time of review: bucket i counts reviews
+	// with playtimes below PlaytimeBucketHours[i] hours (and not in an
+	// earlier bucket). The last bucket counts the rest.
+	PositiveByPlaytime []int
+	NegativeByPlaytime []int
+	// The total playtime at review of all the reviews, in minutes.
+	TotalPlaytime int64
+}
+
+// Method Add counts one review.
+func (rs *ReviewStats) Add(r *Review) {
+	if rs.PositiveByPlaytime == nil {
+		rs.PositiveByPlaytime = make([]int, len(PlaytimeBucketHours)+1)
+		rs.NegativeByPlaytime = make([]int, len(PlaytimeBucketHours)+1)
+	}
+	minutes := r.Author.PlaytimeAtReview
+	bucket := len(PlaytimeBucketHours)
+	for i, hours := range PlaytimeBucketHours {
+		if float64(minutes) < hours*60 {
+			bucket = i
+			break
+		}
+	}
+	if r.VotedUp {
+		rs.Positive++
+		rs.PositiveByPlaytime[bucket]++
+	} else {
+		rs.Negative++
+		rs.NegativeByPlaytime[bucket]++
+	}
+	rs.TotalPlaytime += int64(minutes)
+}
+
+// Method Total returns the number of reviews counted.
+func (rs *ReviewStats) Total() int { return rs.Positive + rs.Negative }
+
+// Method PositiveRatio returns the fraction of the reviews which are positive,
+// or 0 if there are none.
+func (rs *ReviewStats) PositiveRatio() float64 {
+	if rs.Total() == 0 {
+		return 0
+	}
+	return float64(rs.Positive) / float64(rs.Total())
+}
+
+// Method MeanPlaytimeHours returns the mean playtime at review, in hours.
+func (rs *ReviewStats) MeanPlaytimeHours() float64 {
+	if rs.Total() == 0 {
+		return 0
+	}
+	return float64(rs.TotalPlaytime) / 60 / float64(rs.Total())
+}
+
+// Method BucketLabel returns a label for playtime bucket i, like "5-10h".
+func (rs *ReviewStats) BucketLabel(i int) string {
+	switch {
+	case i == 0:
+		return fmt.Sprintf("<%gh", PlaytimeBucketHours[0])
+	case i >= len(PlaytimeBucketHours):
+		return fmt.Sprintf("%gh+", PlaytimeBucketHours[len(PlaytimeBucketHours)-1])
+	}
+	return fmt.Sprintf("%g-%gh", PlaytimeBucketHours[i-1], PlaytimeBucketHours[i])
+}
+
+// Method Report returns a human-readable summary of rs, with one line per
+// playtime bucket.
+func (rs *ReviewStats) Report() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d reviews: %d positive, %d negative (%.1f%% positive)\n",
+		rs.Total(), rs.Positive, rs.Negative, 100*rs.PositiveRatio())
+	fmt.Fprintf(&sb, "mean playtime at review: %.1fh\n", rs.MeanPlaytimeHours())
+	for i := range rs.PositiveByPlaytime {
+		fmt.Fprintf(&sb, "%8s %7d positive %7d negative\n", rs.BucketLabel(i),
+			rs.PositiveByPlaytime[i], rs.NegativeByPlaytime[i])
+	}
+	return sb.String()
+}
+
+// Function SummarizeReviews reads the rest of the reviews from it and returns
+// statistics about them, along with the totals Steam reported.
+func SummarizeReviews(it *ReviewIterator) (*ReviewStats, *ReviewQuerySummary, error) {
+	stats := &ReviewStats{}
+	for it.Next() {
+		stats.Add(it.Review())
+	}
+	return stats, it.Summary(), it.Err()
+}
+
+/*================================= Helpers ==================================*/
+
+// looseFloat is a float64 which can be written in JSON as a number or as a
+// string, as Steam does for weighted_vote_score (see Review.UnmarshalJSON).
+type looseFloat float64
+
+func (f *looseFloat) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		s = string(data)
+	}
+	if s == "" || s == "null" {
+		*f = 0
+		return nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return err
+	}
+	*f = looseFloat(v)
+	return nil
+}
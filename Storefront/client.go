@@ -0,0 +1,231 @@
+package Storefront
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	steamAPI "github.com/c12h/SteamAPI"
+)
+
+// DefaultBaseURL is the scheme and host of the store API.
+const DefaultBaseURL = "https://store.steampowered.com"
+
+// DefaultMinInterval is the least time between requests from one Client,
+// unless its MinInterval field says otherwise. The store throttles clients
+// which send more than about 200 requests in 5 minutes.
+const DefaultMinInterval = 1500 * time.Millisecond
+
+// DefaultCacheMaxAge is how long a Client reuses cached answers, unless its
+// CacheMaxAge field says otherwise.
+const DefaultCacheMaxAge = 24 * time.Hour
+
+// Type Client holds the settings used to make requests to the store API. The
+// zero value is ready to use.
+type Client struct {
+	// The client used to send requests; nil means steamAPI.DefaultClient.
+	API *steamAPI.Client
+	// The scheme and host for requests; "" means DefaultBaseURL.
+	BaseURL string
+	// Where to cache answers; "" means the directory "Storefront" under
+	// steamAPI.CacheDirPath().
+	CacheDir string
+	// How long to reuse cached answers; 0 means DefaultCacheMaxAge, and a
+	// negative value turns the cache off.
+	CacheMaxAge time.Duration
+	// The least time between requests; 0 means DefaultMinInterval, and a
+	// negative value turns the rate limit off. This limit is separate from
+	// any steamAPI.Quota, which only counts Web API calls.
+	MinInterval time.Duration
+
+	mu          sync.Mutex
+	nextRequest time.Time
+}
+
+// DefaultClient is the Client used by the package-level functions.
+var DefaultClient = &Client{}
+
+// Type Options holds the settings which change the store's answers.
+type Options struct {
+	CC       string // Country code, like "us" or "au"; "" means Steam's guess
+	Language string // Language name, like "english" or "german"; "" means English
+}
+
+func (o *Options) cc() string {
+	if o == nil {
+		return ""
+	}
+	return strings.ToLower(o.CC)
+}
+
+func (o *Options) language() string {
+	if o == nil {
+		return ""
+	}
+	return strings.ToLower(o.Language)
+}
+
+func (c *Client) api() *steamAPI.Client {
+	if c.API != nil {
+		return c.API
+	}
+	return steamAPI.DefaultClient
+}
+
+func (c *Client) baseURL() string {
+	if c.BaseURL != "" {
+		return strings.TrimSuffix(c.BaseURL, "/")
+	}
+	return DefaultBaseURL
+}
+
+func (c *Client) cacheDir() string {
+	if c.CacheDir != "" {
+		return c.CacheDir
+	}
+	return filepath.Join(steamAPI.CacheDirPath(), "Storefront")
+}
+
+func (c *Client) cacheMaxAge() time.Duration {
+	if c.CacheMaxAge != 0 {
+		return c.CacheMaxAge
+	}
+	return DefaultCacheMaxAge
+}
+
+// cachePath returns the path of the cache file for the answer about one item,
+// such as an app, in one country and language.
+func (c *Client) cachePath(kind string, id fmt.Stringer, opts *Options) string {
+	cc, lang := opts.cc(), opts.language()
+	if cc == "" {
+		cc = "default"
+	}
+	if lang == "" {
+		lang = "default"
+	}
+	return filepath.Join(c.cacheDir(), kind,
+		fmt.Sprintf("%s-%s-%s.json", id, url.PathEscape(cc), url.PathEscape(lang)))
+}
+
+// readCache decodes the cached answer at path into outvar, if there is a
+// fresh one, logging any problems.
+func (c *Client) readCache(path string, outvar interface{}) bool {
+	if c.cacheMaxAge() < 0 {
+		return false
+	}
+	found, err := steamAPI.ReadCachedJSON(path, c.cacheMaxAge(), outvar)
+	if err != nil {
+		c.logf("Ignoring cached store data: %s", err)
+	}
+	return found
+}
+
+// writeCache saves value at path, logging any problems.
+func (c *Client) writeCache(path string, value interface{}) {
+	if c.cacheMaxAge() < 0 {
+		return
+	}
+	if err := steamAPI.WriteCachedJSON(path, value); err != nil {
+		c.logf("Cannot cache store data: %s", err)
+	}
+}
+
+// getJSON fetches BaseURL+path with the given parameters (plus cc and l from
+// opts) and decodes the answer into outvar.
+func (c *Client) getJSON(ctx context.Context, outvar interface{},
+	what, who, path string, params url.Values, opts *Options,
+) error {
+	if cc := opts.cc(); cc != "" {
+		params.Set("cc", cc)
+	}
+	if lang := opts.language(); lang != "" {
+		params.Set("l", lang)
+	}
+	requestURL := c.baseURL() + path + "?" + params.Encode()
+	// Retries must wait their turn too.
+	wait := func() error {
+		if err := c.waitTurn(ctx); err != nil {
+			return &steamAPI.WebError{Action: "get", What: what, Who: who,
+				BaseError: err}
+		}
+		return nil
+	}
+	return c.api().GetURLJSONPaced(ctx, outvar, what, who, requestURL,
+		steamAPI.UseRetries, wait)
+}
+
+// waitTurn waits until c may send another request under its rate limit, or
+// until ctx is done.
+func (c *Client) waitTurn(ctx context.Context) error {
+	interval := c.MinInterval
+	if interval == 0 {
+		interval = DefaultMinInterval
+	} else if interval < 0 {
+		return nil
+	}
+	c.mu.Lock()
+	now := time.Now()
+	slot := c.nextRequest
+	if slot.Before(now) {
+		slot = now
+	}
+	c.nextRequest = slot.Add(interval)
+	c.mu.Unlock()
+
+	if wait := slot.Sub(now); wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+func (c *Client) logf(format string, args ...interface{}) {
+	if logf := c.api().Logf; logf != nil {
+		logf(format, args...)
+	}
+}
+
+/*================================== Errors ==================================*/
+
+// ErrNotAvailable is wrapped by the errors returned for items which the store
+// has no details for (in the country asked about, at least).
+var ErrNotAvailable = errors.New("not available from the Steam store")
+
+// Type NotAvailableError is the error returned when the store reports failure
+// (“success”: false) for an item. It matches ErrNotAvailable for errors.Is.
+type NotAvailableError struct {
+	What string // The kind of item, like "app"
+	ID   string // The item's ID
+	CC   string // The country code asked about, if any
+}
+
+func (e *NotAvailableError) Error() string {
+	where := ""
+	if e.CC != "" {
+		where = " in country " + strings.ToUpper(e.CC)
+	}
+	return fmt.Sprintf("%s %s is %s%s", e.What, e.ID, ErrNotAvailable, where)
+}
+
+func (e *NotAvailableError) Is(target error) bool { return target == ErrNotAvailable }
+
+// Type KindError is the error returned when a StoreItem of the wrong kind is
+// passed to a function, such as an app to GetPackageDetails.
+type KindError struct {
+	Item steamAPI.StoreItem
+	Want steamAPI.StoreItemKind
+}
+
+func (e *KindError) Error() string {
+	return fmt.Sprintf("store item %s is not a %s", e.Item, e.Want)
+}
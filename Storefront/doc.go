@@ -0,0 +1,17 @@
+// Package Storefront provides typed access to Steam’s unofficial store API, as
+// used by the Steam store’s own web pages.
+//
+// Unlike the Web API, the store API needs no key and is not covered by the
+// Web API’s daily call limit, but it is undocumented, can change without
+// notice, and throttles clients which send requests too quickly. What is known
+// about it is collected at https://wiki.teamfortress.com/wiki/User:RJackson/StorefrontAPI.
+//
+// The store’s answers depend on the country (which sets the currency and which
+// apps are available) and on the language. A Client caches them on disk,
+// per item, country and language, under CacheDirPath(). It also spaces out its
+// requests (see Client.MinInterval) to stay under the store’s rate limit.
+//
+// A Client sends its requests via a steamAPI.Client, so it shares that client’s
+// HTTP settings, retry policy and logging.
+//
+package Storefront // import "github.com/c12h/SteamAPI/Storefront"
@@ -0,0 +1,143 @@
+package Storefront
+
+// This file provides /api/packagedetails, which describes one sub (AKA
+// package): the apps it contains and its price.
+
+import (
+	"context"
+	"errors"
+	"net/url"
+
+	steamAPI "github.com/c12h/SteamAPI"
+)
+
+// Type PackageDetails holds what the store says about a sub.
+type PackageDetails struct {
+	ID          steamAPI.SteamItemID `json:"-"` // Filled in by GetPackageDetails
+	Name        string               `json:"name"`
+	PageImage   string               `json:"page_image"`
+	HeaderImage string               `json:"header_image"`
+	SmallLogo   string               `json:"small_logo"`
+	Apps        []PackageApp         `json:"apps"`
+	Price       *PackagePrice        `json:"price"` // nil if free or unpriced
+	Platforms   Platforms            `json:"platforms"`
+	ReleaseDate ReleaseDate          `json:"release_date"`
+
+	// The ways to buy the sub, from the store pages of the apps it contains.
+	PurchaseOptions []PurchaseOption `json:"purchase_options,omitempty"`
+}
+
+// Type PackageApp identifies one app in a sub.
+type PackageApp struct {
+	ID   steamAPI.SteamItemID `json:"id"`
+	Name string               `json:"name"`
+}
+
+// Type PackagePrice holds a sub's price in one country, in the smallest unit
+// of the currency.
+type PackagePrice struct {
+	Currency        string `json:"currency"`
+	Initial         int    `json:"initial"` // Before any discount
+	Final           int    `json:"final"`   // After any discount
+	DiscountPercent int    `json:"discount_percent"`
+	Individual      int    `json:"individual"` // The apps' prices added up
+}
+
+// Type PackageGroup holds one group of purchase options from an app's store
+// page, such as the "default" group of subs which contain the app.
+type PackageGroup struct {
+	Name          string           `json:"name"`
+	Title         string           `json:"title"`
+	Description   string           `json:"description"`
+	SelectionText string           `json:"selection_text"`
+	Subs          []PurchaseOption `json:"subs"`
+}
+
+// Type PurchaseOption describes one way to buy something on an app's store
+// page.
+type PurchaseOption struct {
+	PackageID          steamAPI.SteamItemID `json:"packageid"`
+	OptionText         string               `json:"option_text"` // Like "Game - $9.99"
+	OptionDescription  string               `json:"option_description"`
+	PercentSavings     int                  `json:"percent_savings"`
+	PercentSavingsText string               `json:"percent_savings_text"`
+	IsFreeLicense      bool                 `json:"is_free_license"`
+	PriceWithDiscount  int                  `json:"price_in_cents_with_discount"`
+}
+
+// Method Item returns the sub the option would buy, as a StoreItem.
+func (po *PurchaseOption) Item() steamAPI.StoreItem {
+	return steamAPI.SubItem(po.PackageID)
+}
+
+type packageDetailsEntry struct {
+	Success bool            `json:"success"`
+	Data    *PackageDetails `json:"data,omitempty"`
+}
+
+// Function GetPackageDetails calls DefaultClient.GetPackageDetails.
+func GetPackageDetails(ctx context.Context, sub steamAPI.StoreItem, opts *Options,
+) (*PackageDetails, error) {
+	return DefaultClient.GetPackageDetails(ctx, sub, opts)
+}
+
+// Method GetPackageDetails returns the store's details of a sub, for the
+// country and language in opts (which can be nil), using a cached answer if
+// there is a fresh one. It returns a *KindError if 'sub' is not a sub, and a
+// *NotAvailableError if the store has no details for it.
+//
+// The store does not list the purchase options for a sub as such, so
+// GetPackageDetails fills in PurchaseOptions from the store details of the
+// first app in the sub which offers that sub for sale (using GetAppDetails,
+// which has its own cache).
+//
+func (c *Client) GetPackageDetails(ctx context.Context, sub steamAPI.StoreItem,
+	opts *Options,
+) (*PackageDetails, error) {
+	if sub.Kind != steamAPI.StoreSub {
+		return nil, &KindError{Item: sub, Want: steamAPI.StoreSub}
+	}
+	path := c.cachePath("packagedetails", sub.ID, opts)
+	var entry packageDetailsEntry
+	if !c.readCache(path, &entry) {
+		var resp map[string]packageDetailsEntry
+		err := c.getJSON(ctx, &resp, "package details", sub.String(),
+			"/api/packagedetails/", url.Values{"packageids": {sub.ID.String()}},
+			opts)
+		if err != nil {
+			return nil, err
+		}
+		entry = resp[sub.ID.String()]
+		if entry.Success && entry.Data == nil {
+			entry.Success = false
+		}
+		c.writeCache(path, &entry)
+	}
+	if !entry.Success {
+		return nil, &NotAvailableError{What: "sub", ID: sub.ID.String(),
+			CC: opts.cc()}
+	}
+	details := entry.Data
+	details.ID = sub.ID
+
+	for _, app := range details.Apps {
+		ad, err := c.GetAppDetails(ctx, app.ID, opts)
+		if errors.Is(err, ErrNotAvailable) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		for _, group := range ad.PackageGroups {
+			for _, option := range group.Subs {
+				if option.PackageID == sub.ID {
+					details.PurchaseOptions = append(details.PurchaseOptions,
+						option)
+				}
+			}
+		}
+		if len(details.PurchaseOptions) > 0 {
+			break
+		}
+	}
+	return details, nil
+}
@@ -0,0 +1,266 @@
+package Storefront
+
+// This file provides PriceTracker, which records the prices of a set of apps
+// over time in local files and answers questions about their history.
+//
+// The history of each app in each country lives in its own file, holding one
+// JSON object per line, under CacheDirPath(). Files are only ever appended to,
+// so an interrupted write can at worst leave a partial last line, which
+// History ignores. The next append starts a new line after it.
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	steamAPI "github.com/c12h/SteamAPI"
+)
+
+// Type PriceTracker takes snapshots of the prices of some apps in some
+// countries, and keeps them in files under Dir.
+type PriceTracker struct {
+	Client    *Client                // nil means DefaultClient
+	Dir       string                 // "" means "PriceHistory" under CacheDirPath()
+	Apps      []steamAPI.SteamItemID // The apps to watch
+	Countries []string               // Two-letter country codes
+}
+
+// Type PriceSnapshot records the price of one app in one country at one time.
+// The price fields are zero if HasPrice is false.
+type PriceSnapshot struct {
+	Time            int64                `json:"time"` // Unix time
+	App             steamAPI.SteamItemID `json:"app"`
+	CC              string               `json:"cc"`
+	Available       bool                 `json:"available"`
+	HasPrice        bool                 `json:"has_price"`
+	Currency        string               `json:"currency,omitempty"`
+	Initial         int                  `json:"initial,omitempty"`
+	Final           int                  `json:"final,omitempty"`
+	DiscountPercent int                  `json:"discount_percent,omitempty"`
+}
+
+// Method When returns the time of the snapshot.
+func (ps *PriceSnapshot) When() time.Time { return time.Unix(ps.Time, 0) }
+
+func (pt *PriceTracker) client() *Client {
+	if pt.Client != nil {
+		return pt.Client
+	}
+	return DefaultClient
+}
+
+func (pt *PriceTracker) dir() string {
+	if pt.Dir != "" {
+		return pt.Dir
+	}
+	return filepath.Join(steamAPI.CacheDirPath(), "PriceHistory")
+}
+
+func (pt *PriceTracker) historyPath(app steamAPI.SteamItemID, cc string) string {
+	return filepath.Join(pt.dir(), strings.ToLower(cc), app.String()+".jsonl")
+}
+
+/*============================= Taking Snapshots =============================*/
+
+// Method Snapshot fetches the current prices of pt.Apps in pt.Countries (with
+// PriceSurvey), appends them to the history files, and returns them.
+func (pt *PriceTracker) Snapshot(ctx context.Context) ([]PriceSnapshot, error) {
+	table, err := pt.client().PriceSurvey(ctx, pt.Apps, pt.Countries)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().Unix()
+	snapshots := make([]PriceSnapshot, len(table.Prices))
+	for i, p := range table.Prices {
+		s := PriceSnapshot{Time: now, App: p.App, CC: p.CC, Available: p.Available}
+		if p.Price != nil {
+			s.HasPrice = true
+			s.Currency, s.Initial, s.Final = p.Price.Currency, p.Price.Initial,
+				p.Price.Final
+			s.DiscountPercent = p.Price.DiscountPercent
+		}
+		if err := pt.appendSnapshot(&s); err != nil {
+			return nil, err
+		}
+		snapshots[i] = s
+	}
+	return snapshots, nil
+}
+
+func (pt *PriceTracker) appendSnapshot(s *PriceSnapshot) error {
+	path := pt.historyPath(s.App, s.CC)
+	line, err := json.Marshal(s)
+	if err != nil {
+		return &steamAPI.CacheError{Action: "encode", Path: path, BaseError: err}
+	}
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o744); err != nil {
+		return &steamAPI.CacheError{Action: "create directory", Path: dir,
+			BaseError: err}
+	}
+	fh, err := os.OpenFile(path, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return &steamAPI.CacheError{Action: "open", Path: path, BaseError: err}
+	}
+	if endsMidLine(fh) {
+		line = append([]byte{'\n'}, line...)
+	}
+	_, err = fh.Write(append(line, '\n'))
+	if closeErr := fh.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return &steamAPI.CacheError{Action: "append to", Path: path, BaseError: err}
+	}
+	return nil
+}
+
+// endsMidLine reports whether the file fh is not empty and does not end with a
+// newline, as happens if a write is cut short.
+func endsMidLine(fh *os.File) bool {
+	fi, err := fh.Stat()
+	if err != nil || fi.Size() == 0 {
+		return false
+	}
+	last := make([]byte, 1)
+	_, err = fh.ReadAt(last, fi.Size()-1)
+	return err == nil && last[0] != '\n'
+}
+
+// Method Run calls Snapshot straight away and then every 'interval' until ctx
+// is done, which is the only way it returns (except for an error straight
+// away if interval is not positive). Failed snapshots are logged (via the
+// steamAPI.Client's Logf) and otherwise ignored.
+//
+func (pt *PriceTracker) Run(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("price snapshot interval %s is not positive", interval)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := pt.Snapshot(ctx); err != nil && ctx.Err() == nil {
+			pt.client().logf("Price snapshot failed: %s", err)
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+/*================================= Queries ==================================*/
+
+// Method History returns the recorded snapshots for app in country cc, oldest
+// first. Lines which cannot be parsed, such as a last line cut short by a
+// crash, are skipped.
+//
+func (pt *PriceTracker) History(app steamAPI.SteamItemID, cc string,
+) ([]PriceSnapshot, error) {
+	path := pt.historyPath(app, cc)
+	fh, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, &steamAPI.CacheError{Action: "open", Path: path, BaseError: err}
+	}
+	defer fh.Close()
+
+	var history []PriceSnapshot
+	scanner := bufio.NewScanner(fh)
+	for scanner.Scan() {
+		var s PriceSnapshot
+		if json.Unmarshal(scanner.Bytes(), &s) == nil {
+			history = append(history, s)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, &steamAPI.CacheError{Action: "read", Path: path, BaseError: err}
+	}
+	return history, nil
+}
+
+// Type PriceStats summarises the price history of one app in one country.
+//
+// Prices in different currencies cannot be compared, so the fields other than
+// Snapshots and Current only cover snapshots in the same currency as Current.
+//
+type PriceStats struct {
+	Snapshots int            // The number of snapshots
+	Current   *PriceSnapshot // The latest snapshot with a price, if any
+	Lowest    *PriceSnapshot // The first snapshot with the lowest final price
+	Best      *PriceSnapshot // The first snapshot with the biggest discount
+
+	Priced     int // Snapshots with a price
+	Discounted int // Snapshots with a discount
+	// How many times a discount started: the number of discounted snapshots
+	// which follow an undiscounted one (or come first).
+	DiscountPeriods int
+}
+
+// Method DiscountFrequency returns the fraction of priced snapshots which show
+// a discount. If snapshots were taken regularly, this is roughly the fraction
+// of the time the app has been on sale.
+func (ps *PriceStats) DiscountFrequency() float64 {
+	if ps.Priced == 0 {
+		return 0
+	}
+	return float64(ps.Discounted) / float64(ps.Priced)
+}
+
+// Method IsAllTimeBest reports whether the current discount is at least as big
+// as any recorded before (and is a discount at all).
+func (ps *PriceStats) IsAllTimeBest() bool {
+	return ps.Current != nil && ps.Best != nil && ps.Current.DiscountPercent > 0 &&
+		ps.Current.DiscountPercent >= ps.Best.DiscountPercent
+}
+
+// Method Stats summarises the recorded price history of app in country cc. It
+// returns a PriceStats with Current nil if no snapshot has a price.
+func (pt *PriceTracker) Stats(app steamAPI.SteamItemID, cc string,
+) (*PriceStats, error) {
+	history, err := pt.History(app, cc)
+	if err != nil {
+		return nil, err
+	}
+	stats := &PriceStats{Snapshots: len(history)}
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].HasPrice {
+			stats.Current = &history[i]
+			break
+		}
+	}
+	if stats.Current == nil {
+		return stats, nil
+	}
+
+	wasDiscounted := false
+	for i := range history {
+		s := &history[i]
+		if !s.HasPrice || s.Currency != stats.Current.Currency {
+			continue
+		}
+		stats.Priced++
+		if stats.Lowest == nil || s.Final < stats.Lowest.Final {
+			stats.Lowest = s
+		}
+		if stats.Best == nil || s.DiscountPercent > stats.Best.DiscountPercent {
+			stats.Best = s
+		}
+		isDiscounted := s.DiscountPercent > 0
+		if isDiscounted {
+			stats.Discounted++
+			if !wasDiscounted {
+				stats.DiscountPeriods++
+			}
+		}
+		wasDiscounted = isDiscounted
+	}
+	return stats, nil
+}
@@ -0,0 +1,195 @@
+package Storefront
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	steamAPI "github.com/c12h/SteamAPI"
+)
+
+const testApp = steamAPI.SteamItemID(440)
+
+// priceServer is a stand-in for /api/appdetails with filters=price_overview,
+// which gives testApp the price in price, whatever the country.
+type priceServer struct {
+	price PriceOverview
+}
+
+func (ps *priceServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/api/appdetails/" ||
+		r.URL.Query().Get("filters") != "price_overview" {
+		http.NotFound(w, r)
+		return
+	}
+	fmt.Fprintf(w, `{"%s":{"success":true,"data":{"price_overview":`+
+		`{"currency":%q,"initial":%d,"final":%d,"discount_percent":%d}}}}`,
+		testApp, ps.price.Currency, ps.price.Initial, ps.price.Final,
+		ps.price.DiscountPercent)
+}
+
+// newTestTracker returns a PriceTracker for testApp in the US which uses a
+// stand-in server and a temporary directory. Call the function it returns
+// when done.
+func newTestTracker(t *testing.T) (*PriceTracker, *priceServer, func()) {
+	dir, err := ioutil.TempDir("", "prices")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ps := &priceServer{}
+	server := httptest.NewServer(ps)
+	pt := &PriceTracker{
+		Client: &Client{API: &steamAPI.Client{}, BaseURL: server.URL,
+			CacheMaxAge: -1, MinInterval: -1},
+		Dir:       dir,
+		Apps:      []steamAPI.SteamItemID{testApp},
+		Countries: []string{"US"},
+	}
+	return pt, ps, func() {
+		server.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+// snapshot sets the stand-in server's price and takes a snapshot.
+func snapshot(t *testing.T, pt *PriceTracker, ps *priceServer,
+	currency string, initial, discount int,
+) {
+	t.Helper()
+	ps.price = PriceOverview{Currency: currency, Initial: initial,
+		Final: initial * (100 - discount) / 100, DiscountPercent: discount}
+	snapshots, err := pt.Snapshot(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshots) != 1 || !snapshots[0].HasPrice ||
+		snapshots[0].Final != ps.price.Final || snapshots[0].CC != "us" {
+		t.Fatalf("Snapshot returned %+v, want one for price %+v", snapshots, ps.price)
+	}
+}
+
+func stats(t *testing.T, pt *PriceTracker) *PriceStats {
+	t.Helper()
+	stats, err := pt.Stats(testApp, "us")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return stats
+}
+
+func TestPriceTrackerStats(t *testing.T) {
+	pt, ps, cleanup := newTestTracker(t)
+	defer cleanup()
+
+	snapshot(t, pt, ps, "USD", 1000, 0)
+	snapshot(t, pt, ps, "USD", 1000, 25)
+	snapshot(t, pt, ps, "USD", 1000, 50)
+	snapshot(t, pt, ps, "USD", 1000, 0)
+	snapshot(t, pt, ps, "USD", 1000, 40)
+
+	history, err := pt.History(testApp, "us")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 5 {
+		t.Fatalf("History has %d snapshots, want 5", len(history))
+	}
+	s := stats(t, pt)
+	if s.Snapshots != 5 || s.Priced != 5 || s.Discounted != 3 {
+		t.Errorf("got %d snapshots, %d priced, %d discounted; want 5, 5, 3",
+			s.Snapshots, s.Priced, s.Discounted)
+	}
+	if s.Current == nil || s.Current.DiscountPercent != 40 {
+		t.Errorf("Current = %+v, want the 40%% discount", s.Current)
+	}
+	if s.Lowest == nil || s.Lowest.Final != 500 {
+		t.Errorf("Lowest = %+v, want final price 500", s.Lowest)
+	}
+	if s.Best == nil || s.Best.DiscountPercent != 50 {
+		t.Errorf("Best = %+v, want the 50%% discount", s.Best)
+	}
+	if s.DiscountPeriods != 2 {
+		t.Errorf("DiscountPeriods = %d, want 2", s.DiscountPeriods)
+	}
+	if got := s.DiscountFrequency(); got != 0.6 {
+		t.Errorf("DiscountFrequency() = %g, want 0.6", got)
+	}
+	if s.IsAllTimeBest() {
+		t.Error("IsAllTimeBest() for 40% after 50%")
+	}
+
+	snapshot(t, pt, ps, "USD", 1000, 50)
+	if s := stats(t, pt); !s.IsAllTimeBest() || s.DiscountPeriods != 2 {
+		t.Errorf("after matching the best discount: IsAllTimeBest() = %v,"+
+			" DiscountPeriods = %d; want true, 2", s.IsAllTimeBest(), s.DiscountPeriods)
+	}
+}
+
+func TestPriceTrackerCurrencyChange(t *testing.T) {
+	pt, ps, cleanup := newTestTracker(t)
+	defer cleanup()
+
+	snapshot(t, pt, ps, "USD", 1000, 0)
+	snapshot(t, pt, ps, "USD", 1000, 75)
+	snapshot(t, pt, ps, "EUR", 900, 10)
+
+	s := stats(t, pt)
+	if s.Snapshots != 3 || s.Priced != 1 || s.Discounted != 1 {
+		t.Errorf("got %d snapshots, %d priced, %d discounted; want 3, 1, 1",
+			s.Snapshots, s.Priced, s.Discounted)
+	}
+	if s.Lowest == nil || s.Lowest.Currency != "EUR" || s.Lowest.Final != 810 {
+		t.Errorf("Lowest = %+v, want EUR 810", s.Lowest)
+	}
+	if s.Best == nil || s.Best.DiscountPercent != 10 || !s.IsAllTimeBest() {
+		t.Errorf("Best = %+v, IsAllTimeBest() = %v; want the EUR discount, true",
+			s.Best, s.IsAllTimeBest())
+	}
+}
+
+func TestPriceTrackerPartialLine(t *testing.T) {
+	pt, ps, cleanup := newTestTracker(t)
+	defer cleanup()
+
+	snapshot(t, pt, ps, "USD", 1000, 0)
+	// Simulate a write cut short by a crash.
+	path := pt.historyPath(testApp, "us")
+	fh, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fh.WriteString(`{"time":12345,"app":440,"cc":"us","avail`)
+	fh.Close()
+	snapshot(t, pt, ps, "USD", 1000, 20)
+
+	history, err := pt.History(testApp, "us")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 || history[1].DiscountPercent != 20 {
+		t.Errorf("History after a partial line = %+v, want both snapshots", history)
+	}
+}
+
+func TestPriceTrackerUnwritableDir(t *testing.T) {
+	pt, ps, cleanup := newTestTracker(t)
+	defer cleanup()
+
+	notDir := filepath.Join(pt.Dir, "file")
+	if err := ioutil.WriteFile(notDir, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	pt.Dir = notDir
+	ps.price = PriceOverview{Currency: "USD", Initial: 1000, Final: 1000}
+	_, err := pt.Snapshot(context.Background())
+	var cacheErr *steamAPI.CacheError
+	if !errors.As(err, &cacheErr) {
+		t.Errorf("Snapshot into a file's subdirectory: got %v, want a *CacheError", err)
+	}
+}
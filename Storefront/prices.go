@@ -0,0 +1,156 @@
+package Storefront
+
+// This file provides price lookups for many apps at once, via /api/appdetails
+// with filters=price_overview, and a survey of prices across countries.
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+
+	steamAPI "github.com/c12h/SteamAPI"
+)
+
+// maxPriceAppsPerCall is how many apps GetPrices asks about per request. The
+// store only accepts more than one app per request with filters=price_overview.
+const maxPriceAppsPerCall = 50
+
+// Type AppPrice holds the price of one app in one country, as found by
+// GetPrices.
+type AppPrice struct {
+	App       steamAPI.SteamItemID
+	CC        string         // The country code asked about
+	Available bool           // False if the store has no details for the app
+	Price     *PriceOverview // nil if the app is free, unreleased or unavailable
+}
+
+// Function GetPrices calls DefaultClient.GetPrices.
+func GetPrices(ctx context.Context, apps []steamAPI.SteamItemID, opts *Options,
+) ([]AppPrice, error) {
+	return DefaultClient.GetPrices(ctx, apps, opts)
+}
+
+// Method GetPrices returns the current prices of the given apps in the country
+// in opts (which can be nil), in the same order as apps, asking about up to 50
+// apps per request. Prices are never cached.
+func (c *Client) GetPrices(ctx context.Context, apps []steamAPI.SteamItemID,
+	opts *Options,
+) ([]AppPrice, error) {
+	prices := make([]AppPrice, 0, len(apps))
+	for lo := 0; lo < len(apps); lo += maxPriceAppsPerCall {
+		hi := lo + maxPriceAppsPerCall
+		if hi > len(apps) {
+			hi = len(apps)
+		}
+		ids := make([]string, hi-lo)
+		for i, app := range apps[lo:hi] {
+			ids[i] = app.String()
+		}
+		var resp map[string]struct {
+			Success bool            `json:"success"`
+			Data    json.RawMessage `json:"data"`
+		}
+		err := c.getJSON(ctx, &resp, "prices", fmt.Sprintf("%d apps", hi-lo),
+			"/api/appdetails/", url.Values{"appids": {strings.Join(ids, ",")},
+				"filters": {"price_overview"}}, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, app := range apps[lo:hi] {
+			entry := resp[app.String()]
+			price := AppPrice{App: app, CC: opts.cc(), Available: entry.Success}
+			// For apps without prices, Steam gives "data": [] (!)
+			if entry.Success && bytes.HasPrefix(entry.Data, []byte("{")) {
+				var data struct {
+					PriceOverview *PriceOverview `json:"price_overview"`
+				}
+				err = json.Unmarshal(entry.Data, &data)
+				if err != nil {
+					return nil, &steamAPI.WebError{Action: "decode",
+						What: "price", Who: "app " + app.String(), BaseError: err}
+				}
+				price.Price = data.PriceOverview
+			}
+			prices = append(prices, price)
+		}
+	}
+	return prices, nil
+}
+
+/*================================ The Survey ================================*/
+
+// Type PriceTable holds the prices of some apps in some countries.
+type PriceTable struct {
+	Apps      []steamAPI.SteamItemID
+	Countries []string
+	Prices    []AppPrice // For each country in turn, for each app in turn
+}
+
+// Function PriceSurvey calls DefaultClient.PriceSurvey.
+func PriceSurvey(ctx context.Context, apps []steamAPI.SteamItemID,
+	countries []string,
+) (*PriceTable, error) {
+	return DefaultClient.PriceSurvey(ctx, apps, countries)
+}
+
+// Method PriceSurvey returns the prices of the given apps in each of the given
+// countries (as two-letter country codes), using GetPrices. The requests are
+// sent one at a time, spaced out by c's rate limit, so a survey of many
+// countries takes a while; cancel ctx to give up.
+//
+func (c *Client) PriceSurvey(ctx context.Context, apps []steamAPI.SteamItemID,
+	countries []string,
+) (*PriceTable, error) {
+	table := &PriceTable{Apps: apps, Countries: countries}
+	for _, cc := range countries {
+		prices, err := c.GetPrices(ctx, apps, &Options{CC: cc})
+		if err != nil {
+			return nil, err
+		}
+		table.Prices = append(table.Prices, prices...)
+	}
+	return table, nil
+}
+
+// Method Lookup returns the price of app in country cc, if t has one.
+func (t *PriceTable) Lookup(app steamAPI.SteamItemID, cc string) (*AppPrice, bool) {
+	cc = strings.ToLower(cc)
+	for i := range t.Prices {
+		if t.Prices[i].App == app && t.Prices[i].CC == cc {
+			return &t.Prices[i], true
+		}
+	}
+	return nil, false
+}
+
+// Method WriteCSV writes t to w as CSV, with a header line and then one line
+// per app per country giving the app ID, the country code, whether the app is
+// available there, the currency, the initial and final prices (in the smallest
+// unit of the currency) and the discount percentage. The price fields are
+// empty for apps with no price.
+//
+func (t *PriceTable) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"appid", "cc", "available", "currency",
+		"initial", "final", "discount_percent"})
+	for _, p := range t.Prices {
+		row := []string{p.App.String(), p.CC, strconv.FormatBool(p.Available),
+			"", "", "", ""}
+		if p.Price != nil {
+			row[3] = p.Price.Currency
+			row[4] = strconv.Itoa(p.Price.Initial)
+			row[5] = strconv.Itoa(p.Price.Final)
+			row[6] = strconv.Itoa(p.Price.DiscountPercent)
+		}
+		cw.Write(row)
+	}
+	cw.Flush()
+	return cw.Error()
+}
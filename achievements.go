@@ -0,0 +1,368 @@
+package SteamAPI
+
+// This file provides the ISteamUserStats methods about achievements and stats:
+// GetSchemaForGame (names, descriptions and icons, which we cache per app and
+// language), GetPlayerAchievements, GetUserStatsForGame and
+// GetGlobalAchievementPercentagesForApp, plus GetAchievementView, which joins
+// their results for one user and app.
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SchemaCacheMaxAge is how long GetSchemaForGame trusts a cached schema.
+const SchemaCacheMaxAge = 7 * 24 * time.Hour
+
+type (
+	// Type GameSchema describes the achievements and stats of one app, as
+	// returned by ISteamUserStats/GetSchemaForGame/v2.
+	GameSchema struct {
+		GameName     string              `json:"gameName"`
+		GameVersion  string              `json:"gameVersion"`
+		Achievements []SchemaAchievement `json:"achievements"`
+		Stats        []SchemaStat        `json:"stats"`
+	}
+
+	// Type SchemaAchievement describes one achievement.
+	SchemaAchievement struct {
+		Name         string `json:"name"` // The API name
+		DefaultValue int    `json:"defaultvalue"`
+		DisplayName  string `json:"displayName"`
+		Hidden       int    `json:"hidden"` // 1 if hidden until unlocked
+		Description  string `json:"description"`
+		Icon         string `json:"icon"`     // URL of the unlocked icon
+		IconGray     string `json:"icongray"` // URL of the locked icon
+	}
+
+	// Type SchemaStat describes one stat.
+	SchemaStat struct {
+		Name         string  `json:"name"`
+		DefaultValue float64 `json:"defaultvalue"`
+		DisplayName  string  `json:"displayName"`
+	}
+
+	// Type PlayerAchievement holds one user's progress on one achievement, as
+	// returned by ISteamUserStats/GetPlayerAchievements/v1.
+	PlayerAchievement struct {
+		APIName     string `json:"apiname"`
+		Achieved    int    `json:"achieved"`    // 1 if unlocked
+		UnlockTime  int64  `json:"unlocktime"`  // Unix time, or 0
+		Name        string `json:"name"`        // Only if a language is given
+		Description string `json:"description"` // Only if a language is given
+	}
+
+	// Type UserStats holds one user's stats and unlocked achievements for one
+	// app, as returned by ISteamUserStats/GetUserStatsForGame/v2.
+	UserStats struct {
+		SteamID      SteamID
+		GameName     string
+		Stats        map[string]float64 // By API name
+		Achievements map[string]bool    // By API name; unlocked only
+	}
+)
+
+/*================================= Schemas ==================================*/
+
+// Function GetSchemaForGame calls DefaultClient.GetSchemaForGame.
+func GetSchemaForGame(ctx context.Context, app SteamItemID, language string,
+) (*GameSchema, error) {
+	return DefaultClient.GetSchemaForGame(ctx, app, language)
+}
+
+// Method GetSchemaForGame returns the achievements and stats of an app, with
+// names and descriptions in the given language (such as "english"; "" means
+// Steam's default), from the cache if it has a copy less than SchemaCacheMaxAge
+// old, or else from ISteamUserStats/GetSchemaForGame/v2.
+//
+// Apps without achievements or stats get a GameSchema with empty lists.
+//
+func (c *Client) GetSchemaForGame(ctx context.Context, app SteamItemID,
+	language string,
+) (*GameSchema, error) {
+	langName := language
+	if langName == "" {
+		langName = "default"
+	}
+	cacheDir, err := c.cacheDir()
+	if err != nil {
+		return nil, err
+	}
+	// The language goes into a file name, so it must not contain "/" etc.
+	langName = url.PathEscape(strings.ToLower(langName))
+	path := filepath.Join(cacheDir, "schemas",
+		fmt.Sprintf("%d-%s.json", app, langName))
+	schema := new(GameSchema)
+	found, err := ReadCachedJSON(path, SchemaCacheMaxAge, schema)
+	if found {
+		return schema, nil
+	} else if err != nil {
+		c.logf("ignoring cached schema: %s", err)
+	}
+
+	var resp struct {
+		Game struct {
+			GameName           string `json:"gameName"`
+			GameVersion        string `json:"gameVersion"`
+			AvailableGameStats struct {
+				Achievements []SchemaAchievement `json:"achievements"`
+				Stats        []SchemaStat        `json:"stats"`
+			} `json:"availableGameStats"`
+		} `json:"game"`
+	}
+	params := NewParams().App("appid", app)
+	if language != "" {
+		params.Set("l", language)
+	}
+	err = c.GetJSONValues(ctx, &resp, "achievement schema", "app "+app.String(),
+		"ISteamUserStats", "GetSchemaForGame", 2, UseKey, params.Values())
+	if err != nil {
+		return nil, err
+	}
+	g := &resp.Game
+	schema = &GameSchema{GameName: g.GameName, GameVersion: g.GameVersion,
+		Achievements: g.AvailableGameStats.Achievements,
+		Stats:        g.AvailableGameStats.Stats}
+	err = WriteCachedJSON(path, schema)
+	if err != nil {
+		c.logf("cannot cache schema: %s", err)
+	}
+	return schema, nil
+}
+
+/*================================ User Data =================================*/
+
+// Function GetPlayerAchievements calls DefaultClient.GetPlayerAchievements.
+func GetPlayerAchievements(ctx context.Context, id SteamID, app SteamItemID,
+	language string,
+) ([]PlayerAchievement, error) {
+	return DefaultClient.GetPlayerAchievements(ctx, id, app, language)
+}
+
+// Method GetPlayerAchievements returns user id's progress on each achievement
+// of an app, using ISteamUserStats/GetPlayerAchievements/v1. If language is not
+// "", the results include names and descriptions in that language.
+//
+// If the user's game details are private, it returns an error which wraps
+// ErrPrivateProfile.
+//
+func (c *Client) GetPlayerAchievements(ctx context.Context, id SteamID,
+	app SteamItemID, language string,
+) ([]PlayerAchievement, error) {
+	params := NewParams().SteamID("steamid", id).App("appid", app)
+	if language != "" {
+		params.Set("l", language)
+	}
+	var resp struct {
+		PlayerStats struct {
+			Success      bool                `json:"success"`
+			Error        string              `json:"error"`
+			Achievements []PlayerAchievement `json:"achievements"`
+		} `json:"playerstats"`
+	}
+	who := fmt.Sprintf("user %s in app %s", id, app)
+	err := c.GetJSONValues(ctx, &resp, "achievements", who,
+		"ISteamUserStats", "GetPlayerAchievements", 1, UseKey, params.Values())
+	if err != nil {
+		return nil, markPrivateProfile(err)
+	} else if !resp.PlayerStats.Success {
+		return nil, &WebError{Action: "get", What: "achievements", Who: who,
+			BaseError: fmt.Errorf("Steam says %q", resp.PlayerStats.Error)}
+	}
+	return resp.PlayerStats.Achievements, nil
+}
+
+// Function GetUserStatsForGame calls DefaultClient.GetUserStatsForGame.
+func GetUserStatsForGame(ctx context.Context, id SteamID, app SteamItemID,
+) (*UserStats, error) {
+	return DefaultClient.GetUserStatsForGame(ctx, id, app)
+}
+
+// Method GetUserStatsForGame returns user id's stats and unlocked achievements
+// for an app, using ISteamUserStats/GetUserStatsForGame/v2.
+//
+// If the user's game details are private, it returns an error which wraps
+// ErrPrivateProfile.
+//
+func (c *Client) GetUserStatsForGame(ctx context.Context, id SteamID,
+	app SteamItemID,
+) (*UserStats, error) {
+	var resp struct {
+		PlayerStats struct {
+			SteamID      SteamID `json:"steamID"`
+			GameName     string  `json:"gameName"`
+			Achievements []struct {
+				Name     string `json:"name"`
+				Achieved int    `json:"achieved"`
+			} `json:"achievements"`
+			Stats []struct {
+				Name  string  `json:"name"`
+				Value float64 `json:"value"`
+			} `json:"stats"`
+		} `json:"playerstats"`
+	}
+	who := fmt.Sprintf("user %s in app %s", id, app)
+	err := c.GetJSONValues(ctx, &resp, "stats", who,
+		"ISteamUserStats", "GetUserStatsForGame", 2, UseKey,
+		NewParams().SteamID("steamid", id).App("appid", app).Values())
+	if err != nil {
+		return nil, markPrivateProfile(err)
+	}
+	ps := &resp.PlayerStats
+	stats := &UserStats{SteamID: ps.SteamID, GameName: ps.GameName,
+		Stats:        make(map[string]float64, len(ps.Stats)),
+		Achievements: make(map[string]bool, len(ps.Achievements))}
+	for _, s := range ps.Stats {
+		stats.Stats[s.Name] = s.Value
+	}
+	for _, a := range ps.Achievements {
+		stats.Achievements[a.Name] = a.Achieved != 0
+	}
+	return stats, nil
+}
+
+/*============================== Global Rarity ===============================*/
+
+// Function GetGlobalAchievementPercentagesForApp calls
+// DefaultClient.GetGlobalAchievementPercentagesForApp.
+func GetGlobalAchievementPercentagesForApp(ctx context.Context, app SteamItemID,
+) (map[string]float64, error) {
+	return DefaultClient.GetGlobalAchievementPercentagesForApp(ctx, app)
+}
+
+// Method GetGlobalAchievementPercentagesForApp returns the percentage of
+// players of an app who have unlocked each of its achievements, keyed by API
+// name, using ISteamUserStats/GetGlobalAchievementPercentagesForApp/v2 (which
+// needs no key).
+//
+func (c *Client) GetGlobalAchievementPercentagesForApp(ctx context.Context,
+	app SteamItemID,
+) (map[string]float64, error) {
+	var resp struct {
+		AchievementPercentages struct {
+			Achievements []struct {
+				Name    string          `json:"name"`
+				Percent json.RawMessage `json:"percent"`
+			} `json:"achievements"`
+		} `json:"achievementpercentages"`
+	}
+	err := c.GetJSONValues(ctx, &resp, "global achievement percentages",
+		"app "+app.String(),
+		"ISteamUserStats", "GetGlobalAchievementPercentagesForApp", 2, UseHTTPS,
+		NewParams().App("gameid", app).Values())
+	if err != nil {
+		return nil, err
+	}
+	achievements := resp.AchievementPercentages.Achievements
+	percentages := make(map[string]float64, len(achievements))
+	for _, a := range achievements {
+		percentages[a.Name] = parseLooseFloat(a.Percent)
+	}
+	return percentages, nil
+}
+
+// parseLooseFloat parses a JSON number or a string holding a number (Steam
+// sends percentages both ways), returning 0 for anything else.
+func parseLooseFloat(raw json.RawMessage) float64 {
+	text := string(raw)
+	if unquoted, err := strconv.Unquote(text); err == nil {
+		text = unquoted
+	}
+	f, _ := strconv.ParseFloat(text, 64)
+	return f
+}
+
+/*============================== Merged Views ================================*/
+
+// Type AchievementView combines what Steam says about one achievement in
+// general with one user's progress on it.
+type AchievementView struct {
+	APIName       string
+	DisplayName   string
+	Description   string
+	Hidden        bool
+	Icon          string    // URL of the icon to show: unlocked or locked
+	Achieved      bool      // Whether the user has unlocked it
+	UnlockTime    time.Time // When, or the zero Time
+	GlobalPercent float64   // Percentage of all players who have unlocked it
+}
+
+// Function GetAchievementView calls DefaultClient.GetAchievementView.
+func GetAchievementView(ctx context.Context, id SteamID, app SteamItemID,
+	language string,
+) ([]AchievementView, error) {
+	return DefaultClient.GetAchievementView(ctx, id, app, language)
+}
+
+// Method GetAchievementView returns every achievement of an app, with display
+// names, descriptions and icons (in the given language) from the cached
+// schema, user id's unlock times, and global rarity. The achievements are in
+// schema order.
+//
+func (c *Client) GetAchievementView(ctx context.Context, id SteamID,
+	app SteamItemID, language string,
+) ([]AchievementView, error) {
+	schema, err := c.GetSchemaForGame(ctx, app, language)
+	if err != nil {
+		return nil, err
+	}
+	player, err := c.GetPlayerAchievements(ctx, id, app, "")
+	if err != nil {
+		return nil, err
+	}
+	percentages, err := c.GetGlobalAchievementPercentagesForApp(ctx, app)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := make(map[string]PlayerAchievement, len(player))
+	for _, pa := range player {
+		progress[pa.APIName] = pa
+	}
+	views := make([]AchievementView, 0, len(schema.Achievements))
+	for _, sa := range schema.Achievements {
+		pa := progress[sa.Name]
+		v := AchievementView{APIName: sa.Name, DisplayName: sa.DisplayName,
+			Description: sa.Description, Hidden: sa.Hidden != 0,
+			Icon: sa.IconGray, Achieved: pa.Achieved != 0,
+			GlobalPercent: percentages[sa.Name]}
+		if v.Achieved {
+			v.Icon = sa.Icon
+			if pa.UnlockTime != 0 {
+				v.UnlockTime = time.Unix(pa.UnlockTime, 0)
+			}
+		}
+		views = append(views, v)
+	}
+	return views, nil
+}
+
+// Function SortByRarity sorts views so the rarest achievements come first.
+func SortByRarity(views []AchievementView) {
+	sort.SliceStable(views, func(i, j int) bool {
+		return views[i].GlobalPercent < views[j].GlobalPercent
+	})
+}
+
+// markPrivateProfile makes err wrap ErrPrivateProfile if it is a WebError for
+// HTTP status 403 with a JSON body, which is how some methods report private
+// profiles. Steam also answers 403 for bad keys, but with an HTML page, so
+// other 403s are left alone. Either way, err still matches ErrUnauthorized
+// and keeps the details of the response.
+func markPrivateProfile(err error) error {
+	var webErr *WebError
+	if errors.As(err, &webErr) && webErr.StatusCode == http.StatusForbidden &&
+		webErr.BaseError == nil && strings.HasPrefix(webErr.Excerpt, "{") {
+		webErr.BaseError = ErrPrivateProfile
+	}
+	return err
+}
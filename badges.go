@@ -0,0 +1,168 @@
+package SteamAPI
+
+// This file provides IPlayerService/GetSteamLevel, GetBadges and
+// GetCommunityBadgeProgress, and helpers for Steam's level/XP curve.
+
+import (
+	"context"
+	"fmt"
+)
+
+// Type Badge holds one of a user's badges, as returned by
+// IPlayerService/GetBadges/v1. Game badges have an AppID and a
+// CommunityItemID; badges for events and the like do not.
+//
+type Badge struct {
+	BadgeID         int         `json:"badgeid"`
+	Level           int         `json:"level"`
+	CompletionTime  int64       `json:"completion_time"` // Unix time
+	XP              int         `json:"xp"`
+	Scarcity        int         `json:"scarcity"` // How many users have it
+	AppID           SteamItemID `json:"appid,omitempty"`
+	CommunityItemID string      `json:"communityitemid,omitempty"`
+	BorderColor     int         `json:"border_color,omitempty"` // 1 for foil
+}
+
+// Type BadgeSummary holds the result of GetBadges: a user's badges, total XP
+// and level.
+type BadgeSummary struct {
+	Badges               []Badge `json:"badges"`
+	PlayerXP             int64   `json:"player_xp"`
+	PlayerLevel          int     `json:"player_level"`
+	XPNeededToLevelUp    int64   `json:"player_xp_needed_to_level_up"`
+	XPNeededCurrentLevel int64   `json:"player_xp_needed_current_level"`
+}
+
+// Method LevelProgress returns how far (from 0 to 1) the user is through their
+// current level.
+func (bs *BadgeSummary) LevelProgress() float64 {
+	span := bs.PlayerXP + bs.XPNeededToLevelUp - bs.XPNeededCurrentLevel
+	if span <= 0 {
+		return 0
+	}
+	return float64(bs.PlayerXP-bs.XPNeededCurrentLevel) / float64(span)
+}
+
+// Type BadgeQuest holds the state of one task towards a community badge.
+type BadgeQuest struct {
+	QuestID   int  `json:"questid"`
+	Completed bool `json:"completed"`
+}
+
+/*============================== The XP Curve ================================*/
+
+// Function XPForLevel returns the total XP a user needs to reach 'level'. Each
+// level up to 10 costs 100 XP, each level from 11 to 20 costs 200 XP, and so on.
+func XPForLevel(level int) int64 {
+	if level <= 0 {
+		return 0
+	}
+	tens, rest := int64(level/10), int64(level%10)
+	return 100 * (5*tens*(tens+1) + rest*(tens+1))
+}
+
+// Function LevelForXP returns the level a user with 'xp' XP has reached.
+func LevelForXP(xp int64) int {
+	level := 0
+	for XPForLevel(level+1) <= xp {
+		level++
+	}
+	return level
+}
+
+// Function XPToNextLevel returns how much more XP a user with 'xp' XP needs to
+// reach the next level.
+func XPToNextLevel(xp int64) int64 {
+	if xp < 0 {
+		xp = 0
+	}
+	return XPForLevel(LevelForXP(xp)+1) - xp
+}
+
+/*================================ The Calls =================================*/
+
+// Function GetSteamLevel calls DefaultClient.GetSteamLevel.
+func GetSteamLevel(ctx context.Context, id SteamID) (int, error) {
+	return DefaultClient.GetSteamLevel(ctx, id)
+}
+
+// Method GetSteamLevel returns the Steam level of user 'id', using
+// IPlayerService/GetSteamLevel/v1. If the user's profile is private, it returns
+// an error which wraps ErrPrivateProfile.
+//
+func (c *Client) GetSteamLevel(ctx context.Context, id SteamID) (int, error) {
+	var resp struct {
+		Response struct {
+			PlayerLevel *int `json:"player_level"`
+		} `json:"response"`
+	}
+	err := c.GetJSONValues(ctx, &resp, "Steam level", "user "+id.String(),
+		"IPlayerService", "GetSteamLevel", 1, UseKey,
+		NewParams().SteamID("steamid", id).Values())
+	if err != nil {
+		return 0, err
+	} else if resp.Response.PlayerLevel == nil {
+		return 0, &WebError{Action: "get", What: "Steam level",
+			Who: "user " + id.String(), BaseError: ErrPrivateProfile}
+	}
+	return *resp.Response.PlayerLevel, nil
+}
+
+// Function GetBadges calls DefaultClient.GetBadges.
+func GetBadges(ctx context.Context, id SteamID) (*BadgeSummary, error) {
+	return DefaultClient.GetBadges(ctx, id)
+}
+
+// Method GetBadges returns the badges, XP and level of user 'id', using
+// IPlayerService/GetBadges/v1. If the user's profile is private, it returns an
+// error which wraps ErrPrivateProfile.
+//
+func (c *Client) GetBadges(ctx context.Context, id SteamID) (*BadgeSummary, error) {
+	var resp struct {
+		Response struct {
+			BadgeSummary
+			PlayerXP *int64 `json:"player_xp"`
+		} `json:"response"`
+	}
+	err := c.GetJSONValues(ctx, &resp, "badges", "user "+id.String(),
+		"IPlayerService", "GetBadges", 1, UseKey,
+		NewParams().SteamID("steamid", id).Values())
+	if err != nil {
+		return nil, err
+	} else if resp.Response.PlayerXP == nil {
+		return nil, &WebError{Action: "get", What: "badges",
+			Who: "user " + id.String(), BaseError: ErrPrivateProfile}
+	}
+	summary := resp.Response.BadgeSummary
+	summary.PlayerXP = *resp.Response.PlayerXP
+	return &summary, nil
+}
+
+// Function GetCommunityBadgeProgress calls
+// DefaultClient.GetCommunityBadgeProgress.
+func GetCommunityBadgeProgress(ctx context.Context, id SteamID, badgeID int,
+) ([]BadgeQuest, error) {
+	return DefaultClient.GetCommunityBadgeProgress(ctx, id, badgeID)
+}
+
+// Method GetCommunityBadgeProgress returns the tasks towards community badge
+// 'badgeID' (such as 2, the Steam Community badge) and whether user 'id' has
+// completed each of them, using IPlayerService/GetCommunityBadgeProgress/v1.
+//
+func (c *Client) GetCommunityBadgeProgress(ctx context.Context, id SteamID,
+	badgeID int,
+) ([]BadgeQuest, error) {
+	var resp struct {
+		Response struct {
+			Quests []BadgeQuest `json:"quests"`
+		} `json:"response"`
+	}
+	err := c.GetJSONValues(ctx, &resp,
+		fmt.Sprintf("progress towards badge %d", badgeID), "user "+id.String(),
+		"IPlayerService", "GetCommunityBadgeProgress", 1, UseKey,
+		NewParams().SteamID("steamid", id).Int("badgeid", int64(badgeID)).Values())
+	if err != nil {
+		return nil, err
+	}
+	return resp.Response.Quests, nil
+}
@@ -0,0 +1,102 @@
+package SteamAPI
+
+// This file helps with methods which accept a limited number of IDs per call,
+// by splitting long lists into batches and fetching several batches at once.
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultMaxConcurrency is how many requests a Client sends at once for a
+// batched call, unless its MaxConcurrency field says otherwise.
+const DefaultMaxConcurrency = 4
+
+func (c *Client) maxConcurrency() int {
+	if c.MaxConcurrency > 0 {
+		return c.MaxConcurrency
+	}
+	return DefaultMaxConcurrency
+}
+
+// forEachBatch calls fetch(ctx, lo, hi) for consecutive ranges [lo,hi) of at
+// most size elements covering [0,n), running up to c.maxConcurrency() calls at
+// once. If any call fails, forEachBatch cancels the context passed to the
+// others, skips any calls not yet started, and returns the first error.
+//
+func (c *Client) forEachBatch(ctx context.Context, n, size int,
+	fetch func(ctx context.Context, lo, hi int) error,
+) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type batch struct{ lo, hi int }
+	batches := make(chan batch)
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	workers := c.maxConcurrency()
+	if maxNeeded := (n + size - 1) / size; workers > maxNeeded {
+		workers = maxNeeded
+	}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for b := range batches {
+				if err := fetch(ctx, b.lo, b.hi); err != nil {
+					once.Do(func() { firstErr = err; cancel() })
+				}
+			}
+		}()
+	}
+
+feeding:
+	for lo := 0; lo < n; lo += size {
+		hi := lo + size
+		if hi > n {
+			hi = n
+		}
+		select {
+		case batches <- batch{lo, hi}:
+		case <-ctx.Done():
+			break feeding
+		}
+	}
+	close(batches)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// uniqueSteamIDs returns ids without any duplicates (or zero IDs), keeping the
+// first occurrence of each.
+func uniqueSteamIDs(ids []SteamID) []SteamID {
+	seen := make(map[SteamID]bool, len(ids))
+	unique := make([]SteamID, 0, len(ids))
+	for _, id := range ids {
+		if id != NullSteamID64 && !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// joinSteamIDs returns ids as a comma-separated list, which is how several
+// methods take lists of SteamIDs.
+func joinSteamIDs(ids []SteamID) string {
+	buf := make([]byte, 0, 18*len(ids))
+	for i, id := range ids {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = append(buf, id.String()...)
+	}
+	return string(buf)
+}
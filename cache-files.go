@@ -0,0 +1,111 @@
+package SteamAPI
+
+// This file provides helpers for keeping JSON files under CacheDirPath(), for
+// use by this package and others in this module.
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Function ReadCachedJSON decodes the JSON in file path into outvar and
+// returns true, if that file exists and was written less than maxAge ago.
+// Otherwise, it returns false, and an error if the file exists but cannot be
+// read or decoded.
+//
+func ReadCachedJSON(path string, maxAge time.Duration, outvar interface{}) (
+	bool, error,
+) {
+	fi, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, &CacheError{Action: "examine", Path: path, BaseError: err}
+	} else if time.Since(fi.ModTime()) >= maxAge {
+		return false, nil
+	}
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return false, &CacheError{Action: "read", Path: path, BaseError: err}
+	}
+	err = json.Unmarshal(contents, outvar)
+	if err != nil {
+		return false, &CacheError{Action: "parse", Path: path, BaseError: err}
+	}
+	return true, nil
+}
+
+// Function WriteCachedJSON writes value as JSON to file path, creating any
+// missing directories. Readers never see a partly-written file.
+func WriteCachedJSON(path string, value interface{}) error {
+	contents, err := json.Marshal(value)
+	if err != nil {
+		return &CacheError{Action: "encode", Path: path, BaseError: err}
+	}
+	if err := makeCacheDir(filepath.Dir(path)); err != nil {
+		return err
+	}
+	return writeFileAtomically(path, contents)
+}
+
+// makeCacheDir creates directory path and any missing parents. Unlike
+// EnsureDirExists, it returns a *CacheError rather than panicking.
+func makeCacheDir(path string) error {
+	if err := os.MkdirAll(path, 0o744); err != nil {
+		return &CacheError{Action: "create directory", Path: path, BaseError: err}
+	}
+	return nil
+}
+
+// writeFileAtomically writes contents to a temporary file in the same
+// directory as path, then renames it to path.
+func writeFileAtomically(path string, contents []byte) error {
+	fh, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return &CacheError{Action: "create temporary file for", Path: path,
+			BaseError: err}
+	}
+	tempPath := fh.Name()
+	_, err = fh.Write(contents)
+	if err == nil {
+		err = fh.Sync()
+	}
+	if closeErr := fh.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tempPath, path)
+	}
+	if err != nil {
+		os.Remove(tempPath)
+		return &CacheError{Action: "write", Path: path, BaseError: err}
+	}
+	return nil
+}
+
+/*================================== Errors ==================================*/
+
+// Type CacheError represents a failure to use a file under CacheDirPath().
+type CacheError struct {
+	Action    string // What we were trying to do
+	Path      string // Which file/dir we tried to do that to
+	BaseError error  // Error from lower-level code
+	Problem   string // Iff BaseError is nil: details
+}
+
+func (e *CacheError) Error() string {
+	problem := e.Problem
+	if e.BaseError != nil {
+		problem = e.BaseError.Error()
+		if pe, isPathErr := e.BaseError.(*os.PathError); isPathErr {
+			problem = pe.Unwrap().Error()
+		}
+	}
+	return fmt.Sprintf("cannot %s %q: %s", e.Action, e.Path, problem)
+}
+
+func (e *CacheError) Unwrap() error { return e.BaseError }
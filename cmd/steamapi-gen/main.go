@@ -0,0 +1,300 @@
+// Command steamapi-gen writes a Go package with one function per method listed
+// in a saved copy of the output of ISteamWebAPIUtil/GetSupportedAPIList.
+//
+// Usage:
+//	steamapi-gen [-package name] [-o output.go] supported-APIs.json
+//
+// For each version of each GET method, the generated package has a parameter
+// struct (with one field per parameter, other than "key") and a function which
+// sends the call using a *SteamAPI.Client and decodes the JSON result into a
+// caller-supplied variable. For example, ISteamUser/GetFriendList/v1 becomes
+//	type ISteamUserGetFriendListV1Params struct { Steamid uint64; ... }
+//	func ISteamUserGetFriendListV1(ctx context.Context, c *SteamAPI.Client,
+//		p *ISteamUserGetFriendListV1Params, outvar interface{}) error
+//
+// A nil client means SteamAPI.DefaultClient. Optional parameters are sent only
+// if their fields are not zero. Methods that need HTTP POST are skipped, since
+// the SteamAPI package only sends GETs.
+//
+// To pick up new or changed methods, save a fresh copy of
+//	https://api.steampowered.com/ISteamWebAPIUtil/GetSupportedAPIList/v1/?key=...
+// and run steamapi-gen again.
+//
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	steamAPI "github.com/c12h/SteamAPI"
+)
+
+func main() {
+	packageName := flag.String("package", "steamapi", "name of generated package")
+	outPath := flag.String("o", "", "file to write (default: standard output)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(),
+			"usage: %s [-package name] [-o output.go] supported-APIs.json\n",
+			filepath.Base(os.Args[0]))
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	inPath := flag.Arg(0)
+	list, err := steamAPI.LoadSupportedAPIList(inPath)
+	if err != nil {
+		fatal(err)
+	}
+	source, skipped := generate(list, *packageName, filepath.Base(inPath))
+	code, err := format.Source(source)
+	if err != nil {
+		fatal(fmt.Errorf("cannot format generated code: %s", err))
+	}
+	if *outPath == "" {
+		_, err = os.Stdout.Write(code)
+	} else {
+		err = ioutil.WriteFile(*outPath, code, 0o666)
+	}
+	if err != nil {
+		fatal(err)
+	}
+	if skipped > 0 {
+		fmt.Fprintf(os.Stderr, "%s: skipped %d POST method(s)\n",
+			filepath.Base(os.Args[0]), skipped)
+	}
+}
+
+func fatal(err error) {
+	fmt.Fprintf(os.Stderr, "%s: %s\n", filepath.Base(os.Args[0]), err)
+	os.Exit(1)
+}
+
+/*================================ Generating ================================*/
+
+// generate returns the (unformatted) source of the package, and the number of
+// methods it skipped.
+func generate(list *steamAPI.SupportedAPIList, packageName, sourceName string,
+) ([]byte, int) {
+	body := new(bytes.Buffer)
+	skipped, generated, usesFmt := 0, 0, false
+	for _, ai := range list.Interfaces {
+		for i := range ai.Methods {
+			m := &ai.Methods[i]
+			if m.HTTPMethod != "" && m.HTTPMethod != "GET" {
+				skipped++
+				continue
+			}
+			if generateMethod(body, ai.Name, m) {
+				usesFmt = true
+			}
+			generated++
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	fmt.Fprintf(buf, "// Code generated by steamapi-gen from %s; DO NOT EDIT.\n\n",
+		sourceName)
+	fmt.Fprintf(buf, "// Package %s has typed wrappers for the Steam Web API methods\n",
+		packageName)
+	fmt.Fprintf(buf, "// listed in %s.\n", sourceName)
+	fmt.Fprintf(buf, "package %s\n", packageName)
+	// Every method uses context, net/url and SteamAPI; only some use fmt.
+	if generated > 0 {
+		fmt.Fprintf(buf, "\nimport (\n\t%q\n", "context")
+		if usesFmt {
+			fmt.Fprintf(buf, "\t%q\n", "fmt")
+		}
+		fmt.Fprintf(buf, "\t%q\n", "net/url")
+		fmt.Fprintf(buf, "\n\tsteamAPI %q\n)\n", "github.com/c12h/SteamAPI")
+	}
+	buf.Write(body.Bytes())
+	return buf.Bytes(), skipped
+}
+
+type field struct {
+	goName   string
+	goType   string
+	param    string // The parameter name, without any "[0]"
+	isArray  bool
+	optional bool
+	comment  string
+}
+
+// generateMethod writes the parameter struct and function for m, and reports
+// whether they use package fmt.
+func generateMethod(buf *bytes.Buffer, iface string, m *steamAPI.APIMethod,
+) (usesFmt bool) {
+	funcName := fmt.Sprintf("%s%sV%d", goIdent(iface), goIdent(m.Name), m.Version)
+	typeName := funcName + "Params"
+	callName := fmt.Sprintf("%s/%s/v%d", iface, m.Name, m.Version)
+
+	flags := "steamAPI.UseHTTPS"
+	var fields []field
+	used := map[string]bool{"Values": true} // The name of a method of the struct
+	for _, p := range m.Parameters {
+		if p.Name == "key" {
+			if !p.Optional {
+				flags = "steamAPI.UseKey"
+			}
+			continue
+		}
+		f := field{param: p.Name, optional: p.Optional}
+		if m := regexpArrayParam.FindStringSubmatch(p.Name); m != nil {
+			f.param, f.isArray = m[1], true
+		}
+		f.goName = goIdent(f.param)
+		for used[f.goName] {
+			f.goName += "_"
+		}
+		used[f.goName] = true
+		f.goType = goType(p.Type)
+		if f.isArray {
+			f.goType = "[]" + f.goType
+		}
+		f.comment = fmt.Sprintf("%s (%s", p.Name, p.Type)
+		if p.Optional {
+			f.comment += ", optional"
+		}
+		f.comment += ")"
+		if d := tidyText(p.Description); d != "" {
+			f.comment += ": " + d
+		}
+		fields = append(fields, f)
+	}
+
+	fmt.Fprintf(buf, "\n// %s holds the parameters for %s.\n", typeName, callName)
+	fmt.Fprintf(buf, "type %s struct {\n", typeName)
+	for _, f := range fields {
+		writeComment(buf, "\t", f.comment)
+		fmt.Fprintf(buf, "\t%s %s\n", f.goName, f.goType)
+	}
+	fmt.Fprintf(buf, "}\n\n")
+
+	fmt.Fprintf(buf, "// Values returns the parameters in p as a url.Values.\n")
+	fmt.Fprintf(buf, "func (p *%s) Values() url.Values {\n", typeName)
+	fmt.Fprintf(buf, "\tv := steamAPI.NewParams()\n")
+	if len(fields) > 0 {
+		fmt.Fprintf(buf, "\tif p == nil {\n\t\treturn v.Values()\n\t}\n")
+	}
+	for _, f := range fields {
+		writeSetter(buf, f)
+		if f.isArray || f.goType != "string" {
+			usesFmt = true
+		}
+	}
+	fmt.Fprintf(buf, "\treturn v.Values()\n}\n\n")
+
+	writeComment(buf, "", fmt.Sprintf(
+		"%s calls %s, decoding the JSON result into outvar.", funcName, callName))
+	if d := tidyText(m.Description); d != "" {
+		fmt.Fprintf(buf, "//\n")
+		writeComment(buf, "", d)
+	}
+	fmt.Fprintf(buf, "func %s(ctx context.Context, c *steamAPI.Client,\n", funcName)
+	fmt.Fprintf(buf, "\tp *%s, outvar interface{}) error {\n", typeName)
+	fmt.Fprintf(buf, "\tif c == nil {\n\t\tc = steamAPI.DefaultClient\n\t}\n")
+	fmt.Fprintf(buf, "\treturn c.GetJSONValues(ctx, outvar, %q, \"\",\n", callName)
+	fmt.Fprintf(buf, "\t\t%q, %q, %d, %s, p.Values())\n}\n",
+		iface, m.Name, m.Version, flags)
+	return usesFmt
+}
+
+func writeSetter(buf *bytes.Buffer, f field) {
+	value := "fmt.Sprint(p." + f.goName + ")"
+	if f.goType == "string" {
+		value = "p." + f.goName
+	}
+	switch {
+	case f.isArray:
+		fmt.Fprintf(buf, "\tif len(p.%s) > 0 {\n", f.goName)
+		fmt.Fprintf(buf, "\t\telems := make([]string, len(p.%s))\n", f.goName)
+		fmt.Fprintf(buf, "\t\tfor i, e := range p.%s {\n", f.goName)
+		fmt.Fprintf(buf, "\t\t\telems[i] = fmt.Sprint(e)\n\t\t}\n")
+		fmt.Fprintf(buf, "\t\tv.Array(%q, elems...)\n\t}\n", f.param)
+	case f.optional:
+		fmt.Fprintf(buf, "\tif p.%s != %s {\n", f.goName, zeroValue(f.goType))
+		fmt.Fprintf(buf, "\t\tv.Set(%q, %s)\n\t}\n", f.param, value)
+	default:
+		fmt.Fprintf(buf, "\tv.Set(%q, %s)\n", f.param, value)
+	}
+}
+
+/*================================= Helpers ==================================*/
+
+var (
+	regexpArrayParam = regexp.MustCompile(`^(.*)\[0\]$`)
+	regexpNonIdent   = regexp.MustCompile(`[^A-Za-z0-9]+`)
+	regexpSpaces     = regexp.MustCompile(`\s+`)
+)
+
+// goIdent turns a name like "appids_filter" into an exported Go identifier like
+// "AppidsFilter".
+func goIdent(name string) string {
+	ident := ""
+	for _, part := range regexpNonIdent.Split(name, -1) {
+		if part != "" {
+			ident += strings.ToUpper(part[:1]) + part[1:]
+		}
+	}
+	if ident == "" || (ident[0] >= '0' && ident[0] <= '9') {
+		ident = "X" + ident
+	}
+	return ident
+}
+
+// goType maps the parameter types used by GetSupportedAPIList to Go types.
+// Anything unfamiliar (such as "{enum}" or "{message}") becomes a string.
+func goType(steamType string) string {
+	switch steamType {
+	case "bool", "int32", "int64", "uint32", "uint64", "string":
+		return steamType
+	case "uint8", "int8", "uint16", "int16":
+		return steamType
+	case "float":
+		return "float32"
+	case "double":
+		return "float64"
+	}
+	return "string"
+}
+
+func zeroValue(goType string) string {
+	switch goType {
+	case "bool":
+		return "false"
+	case "string":
+		return `""`
+	}
+	return "0"
+}
+
+func tidyText(text string) string {
+	return strings.TrimSpace(regexpSpaces.ReplaceAllString(text, " "))
+}
+
+// writeComment writes text as a // comment, wrapped at about 80 columns.
+func writeComment(buf *bytes.Buffer, indent, text string) {
+	line := ""
+	for _, word := range strings.Fields(text) {
+		if line != "" && len(indent)*8+len(line)+len(word) > 76 {
+			fmt.Fprintf(buf, "%s// %s\n", indent, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	fmt.Fprintf(buf, "%s// %s\n", indent, line)
+}
@@ -0,0 +1,286 @@
+package SteamAPI
+
+// This file provides a crawler which walks the Steam friend graph breadth-first
+// from one or more users, using GetFriendList, and ways to export the result.
+
+import (
+	"bufio"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// Type FriendGraph holds the part of the friend graph found by CrawlFriends.
+type FriendGraph struct {
+	Roots    []SteamID       // Where the crawl started
+	Depth    map[SteamID]int // Every user found, and their distance from a root
+	Edges    []FriendEdge    // Each friendship found, once
+	Private  []SteamID       // Users whose friend lists are private
+	Complete bool            // Whether the crawl finished
+}
+
+// Type FriendEdge represents a friendship between two users.
+type FriendEdge struct {
+	From  SteamID `json:"from"`
+	To    SteamID `json:"to"`
+	Since int64   `json:"since,omitempty"` // Unix time; 0 if unknown
+}
+
+// Type CrawlOptions holds the optional parameters for CrawlFriends.
+type CrawlOptions struct {
+	MaxDepth int // How far to go from the roots; 0 means 2
+	MaxNodes int // The most users to record; 0 means 1000
+
+	// If CheckpointPath is not "", CrawlFriends saves its progress to that
+	// file every CheckpointEvery calls (0 means 25) and when it stops, and
+	// resumes from it if it exists and has the same roots.
+	CheckpointPath  string
+	CheckpointEvery int
+}
+
+const (
+	defaultCrawlDepth      = 2
+	defaultCrawlNodes      = 1000
+	defaultCheckpointEvery = 25
+)
+
+// crawlState is what CrawlFriends saves in a checkpoint file.
+type crawlState struct {
+	Graph    *FriendGraph
+	Queue    []SteamID // Users whose friend lists are still to be fetched
+	Expanded []SteamID // Users whose friend lists have been fetched
+}
+
+// Function CrawlFriends calls DefaultClient.CrawlFriends.
+func CrawlFriends(ctx context.Context, roots []SteamID, opts *CrawlOptions,
+) (*FriendGraph, error) {
+	return DefaultClient.CrawlFriends(ctx, roots, opts)
+}
+
+// Method CrawlFriends walks the friend graph breadth-first from the given
+// users, fetching the friend lists of users less than opts.MaxDepth steps from
+// a root, and recording at most opts.MaxNodes users. Users with private friend
+// lists are listed in the result's Private field and otherwise skipped.
+// Argument opts can be nil.
+//
+// Each friend list costs one call. If c has a Quota, CrawlFriends stops when
+// the quota runs out; like any other failure (including ctx being cancelled),
+// that makes it save a checkpoint (if asked to) and return the graph so far
+// (with Complete false) along with the error. Calling CrawlFriends again with
+// the same roots and CheckpointPath carries on where it left off.
+//
+func (c *Client) CrawlFriends(ctx context.Context, roots []SteamID,
+	opts *CrawlOptions,
+) (*FriendGraph, error) {
+	if opts == nil {
+		opts = &CrawlOptions{}
+	}
+	maxDepth, maxNodes, every := opts.MaxDepth, opts.MaxNodes, opts.CheckpointEvery
+	if maxDepth <= 0 {
+		maxDepth = defaultCrawlDepth
+	}
+	if maxNodes <= 0 {
+		maxNodes = defaultCrawlNodes
+	}
+	if every <= 0 {
+		every = defaultCheckpointEvery
+	}
+	roots = uniqueSteamIDs(roots)
+
+	state, err := c.loadCrawlState(opts.CheckpointPath, roots)
+	if err != nil {
+		return nil, err
+	}
+	graph := state.Graph
+	if graph.Complete {
+		return graph, nil
+	}
+	expanded := make(map[SteamID]bool, len(state.Expanded))
+	for _, id := range state.Expanded {
+		expanded[id] = true
+	}
+	save := func() error {
+		if opts.CheckpointPath == "" {
+			return nil
+		}
+		state.Expanded = state.Expanded[:0]
+		for id := range expanded {
+			state.Expanded = append(state.Expanded, id)
+		}
+		return WriteCachedJSON(opts.CheckpointPath, state)
+	}
+
+	for calls := 1; len(state.Queue) > 0; calls++ {
+		id := state.Queue[0]
+		friends, err := c.GetFriendList(ctx, id)
+		if errors.Is(err, ErrPrivateProfile) {
+			graph.Private = append(graph.Private, id)
+		} else if err != nil {
+			if saveErr := save(); saveErr != nil {
+				c.logf("%s", saveErr)
+			}
+			return graph, err
+		}
+		state.Queue = state.Queue[1:]
+		if err == nil {
+			// Private users stay unexpanded, so edges to them are kept
+			expanded[id] = true
+		}
+
+		depth := graph.Depth[id] + 1
+		for _, f := range friends {
+			if _, known := graph.Depth[f.SteamID]; !known {
+				if len(graph.Depth) >= maxNodes {
+					continue
+				}
+				graph.Depth[f.SteamID] = depth
+				if depth < maxDepth {
+					state.Queue = append(state.Queue, f.SteamID)
+				}
+			}
+			// If f's list was fetched already, it included this friendship
+			if !expanded[f.SteamID] {
+				graph.Edges = append(graph.Edges,
+					FriendEdge{From: id, To: f.SteamID, Since: f.FriendSince})
+			}
+		}
+		if calls%every == 0 {
+			if err := save(); err != nil {
+				return graph, err
+			}
+		}
+	}
+	graph.Complete = true
+	return graph, save()
+}
+
+// loadCrawlState returns the state saved in the checkpoint file at path, if
+// there is one for the same roots, or a new state otherwise.
+func (c *Client) loadCrawlState(path string, roots []SteamID) (*crawlState, error) {
+	if path != "" {
+		var state crawlState
+		found, err := ReadCachedJSON(path, time.Duration(1<<63-1), &state)
+		if err != nil {
+			return nil, err
+		} else if found && state.Graph != nil && sameSteamIDs(state.Graph.Roots, roots) {
+			c.logf("Resuming friend crawl from %q (%d users, %d queued)",
+				path, len(state.Graph.Depth), len(state.Queue))
+			return &state, nil
+		}
+	}
+	state := &crawlState{Graph: &FriendGraph{Roots: roots,
+		Depth: make(map[SteamID]int)}}
+	for _, id := range roots {
+		state.Graph.Depth[id] = 0
+		state.Queue = append(state.Queue, id)
+	}
+	return state, nil
+}
+
+func sameSteamIDs(a, b []SteamID) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+/*================================ Exporting =================================*/
+
+// Method SortedIDs returns the SteamIDs of all the users in g, in order of
+// depth and then SteamID.
+func (g *FriendGraph) SortedIDs() []SteamID {
+	ids := make([]SteamID, 0, len(g.Depth))
+	for id := range g.Depth {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		di, dj := g.Depth[ids[i]], g.Depth[ids[j]]
+		return di < dj || di == dj && ids[i] < ids[j]
+	})
+	return ids
+}
+
+// Method WriteEdgeList writes the friendships in g to w, one per line, as two
+// SteamIDs and the Unix time the friendship began, separated by tabs.
+func (g *FriendGraph) WriteEdgeList(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for _, e := range g.Edges {
+		fmt.Fprintf(bw, "%s\t%s\t%d\n", e.From, e.To, e.Since)
+	}
+	return bw.Flush()
+}
+
+type (
+	graphML struct {
+		XMLName xml.Name     `xml:"http://graphml.graphdrawing.org/xmlns graphml"`
+		Keys    []graphMLKey `xml:"key"`
+		Graph   graphMLGraph `xml:"graph"`
+	}
+	graphMLKey struct {
+		ID       string `xml:"id,attr"`
+		For      string `xml:"for,attr"`
+		AttrName string `xml:"attr.name,attr"`
+		AttrType string `xml:"attr.type,attr"`
+	}
+	graphMLGraph struct {
+		EdgeDefault string        `xml:"edgedefault,attr"`
+		Nodes       []graphMLNode `xml:"node"`
+		Edges       []graphMLEdge `xml:"edge"`
+	}
+	graphMLNode struct {
+		ID   string        `xml:"id,attr"`
+		Data []graphMLData `xml:"data"`
+	}
+	graphMLEdge struct {
+		Source string        `xml:"source,attr"`
+		Target string        `xml:"target,attr"`
+		Data   []graphMLData `xml:"data,omitempty"`
+	}
+	graphMLData struct {
+		Key   string `xml:"key,attr"`
+		Value string `xml:",chardata"`
+	}
+)
+
+// Method WriteGraphML writes g to w as an undirected GraphML graph, whose nodes
+// have "depth" and "private" attributes and whose edges have a "since"
+// attribute (a Unix time) if Steam gave one.
+//
+func (g *FriendGraph) WriteGraphML(w io.Writer) error {
+	doc := graphML{
+		Keys: []graphMLKey{
+			{ID: "depth", For: "node", AttrName: "depth", AttrType: "int"},
+			{ID: "private", For: "node", AttrName: "private", AttrType: "boolean"},
+			{ID: "since", For: "edge", AttrName: "since", AttrType: "long"},
+		},
+		Graph: graphMLGraph{EdgeDefault: "undirected"},
+	}
+	private := make(map[SteamID]bool, len(g.Private))
+	for _, id := range g.Private {
+		private[id] = true
+	}
+	for _, id := range g.SortedIDs() {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{ID: id.String(),
+			Data: []graphMLData{
+				{Key: "depth", Value: fmt.Sprint(g.Depth[id])},
+				{Key: "private", Value: fmt.Sprint(private[id])},
+			}})
+	}
+	for _, e := range g.Edges {
+		edge := graphMLEdge{Source: e.From.String(), Target: e.To.String()}
+		if e.Since != 0 {
+			edge.Data = []graphMLData{{Key: "since", Value: fmt.Sprint(e.Since)}}
+		}
+		doc.Graph.Edges = append(doc.Graph.Edges, edge)
+	}
+	return writeXML(w, &doc)
+}
@@ -0,0 +1,180 @@
+package SteamAPI
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// friendServer is a stand-in for ISteamUser/GetFriendList. Users missing from
+// lists have private friend lists.
+type friendServer struct {
+	lists map[SteamID][]SteamID
+	mu    sync.Mutex
+	calls map[SteamID]int
+}
+
+func (fs *friendServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id, err := ParseSteamID(r.URL.Query().Get("steamid"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	fs.mu.Lock()
+	fs.calls[id]++
+	fs.mu.Unlock()
+	friends, found := fs.lists[id]
+	if !found {
+		http.Error(w, "<html><body>Unauthorized</body></html>",
+			http.StatusUnauthorized)
+		return
+	}
+	entries := make([]string, len(friends))
+	for i, f := range friends {
+		entries[i] = fmt.Sprintf(
+			`{"steamid":"%s","relationship":"friend","friend_since":%d}`, f, i+1)
+	}
+	fmt.Fprintf(w, `{"friendslist":{"friends":[%s]}}`, strings.Join(entries, ","))
+}
+
+var (
+	crawlRoot    = IndividualSteamID(1001)
+	crawlPrivate = IndividualSteamID(1002)
+	crawlFriend  = IndividualSteamID(1003)
+	crawlFar     = IndividualSteamID(1004)
+)
+
+// newFriendServer returns a server for this graph, in which crawlPrivate's
+// friend list is private:
+//	crawlRoot -- crawlPrivate -- crawlFriend -- crawlRoot
+//	crawlFriend -- crawlFar
+func newFriendServer() (*friendServer, *httptest.Server) {
+	fs := &friendServer{calls: make(map[SteamID]int), lists: map[SteamID][]SteamID{
+		crawlRoot:   {crawlPrivate, crawlFriend},
+		crawlFriend: {crawlRoot, crawlPrivate, crawlFar},
+	}}
+	return fs, httptest.NewServer(fs)
+}
+
+// edgeSet returns the edges of g as sorted "a-b" strings, with a < b.
+func edgeSet(g *FriendGraph) []string {
+	var edges []string
+	for _, e := range g.Edges {
+		a, b := e.From, e.To
+		if b < a {
+			a, b = b, a
+		}
+		edges = append(edges, a.String()+"-"+b.String())
+	}
+	sort.Strings(edges)
+	return edges
+}
+
+func wantEdges(ids ...SteamID) []string {
+	var edges []string
+	for i := 0; i < len(ids); i += 2 {
+		a, b := ids[i], ids[i+1]
+		if b < a {
+			a, b = b, a
+		}
+		edges = append(edges, a.String()+"-"+b.String())
+	}
+	sort.Strings(edges)
+	return edges
+}
+
+func checkGraph(t *testing.T, g *FriendGraph) {
+	t.Helper()
+	want := wantEdges(crawlRoot, crawlPrivate, crawlRoot, crawlFriend,
+		crawlFriend, crawlPrivate, crawlFriend, crawlFar)
+	if got := edgeSet(g); strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("edges = %v, want %v", got, want)
+	}
+	if len(g.Private) != 1 || g.Private[0] != crawlPrivate {
+		t.Errorf("Private = %v, want [%s]", g.Private, crawlPrivate)
+	}
+	wantDepth := map[SteamID]int{crawlRoot: 0, crawlPrivate: 1, crawlFriend: 1,
+		crawlFar: 2}
+	if len(g.Depth) != len(wantDepth) {
+		t.Errorf("found %d users, want %d", len(g.Depth), len(wantDepth))
+	}
+	for id, depth := range wantDepth {
+		if got, found := g.Depth[id]; !found || got != depth {
+			t.Errorf("depth of %s = %d (found: %v), want %d", id, got, found, depth)
+		}
+	}
+	if !g.Complete {
+		t.Error("crawl not complete")
+	}
+}
+
+func TestCrawlFriendsKeepsEdgesToPrivateUsers(t *testing.T) {
+	fs, server := newFriendServer()
+	defer server.Close()
+	c := &Client{BaseURL: server.URL, Key: StaticKey("test")}
+
+	g, err := c.CrawlFriends(context.Background(), []SteamID{crawlRoot}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGraph(t, g)
+	for _, id := range []SteamID{crawlRoot, crawlPrivate, crawlFriend} {
+		if fs.calls[id] != 1 {
+			t.Errorf("fetched friends of %s %d times, want 1", id, fs.calls[id])
+		}
+	}
+	if fs.calls[crawlFar] != 0 {
+		t.Errorf("fetched friends of %s, which is at the depth limit", crawlFar)
+	}
+}
+
+func TestCrawlFriendsResumesFromCheckpoint(t *testing.T) {
+	fs, server := newFriendServer()
+	defer server.Close()
+	dir, err := ioutil.TempDir("", "crawl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	opts := &CrawlOptions{CheckpointPath: filepath.Join(dir, "crawl.json")}
+
+	// A budget of 2 calls lets the first run fetch two friend lists.
+	c := &Client{BaseURL: server.URL, Key: StaticKey("test"),
+		Quota: &Quota{Dir: filepath.Join(dir, "quota"), Budget: 2}}
+	g, err := c.CrawlFriends(context.Background(), []SteamID{crawlRoot}, opts)
+	if !errors.Is(err, ErrQuotaExhausted) {
+		t.Fatalf("first run: got error %v, want ErrQuotaExhausted", err)
+	}
+	if g == nil || g.Complete {
+		t.Fatalf("first run: got graph %+v, want an incomplete one", g)
+	}
+
+	c.Quota = nil
+	g, err = c.CrawlFriends(context.Background(), []SteamID{crawlRoot}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGraph(t, g)
+	for id, n := range fs.calls {
+		if n != 1 {
+			t.Errorf("fetched friends of %s %d times, want 1", id, n)
+		}
+	}
+
+	// Once complete, the checkpoint answers without any calls.
+	server.Close()
+	g, err = c.CrawlFriends(context.Background(), []SteamID{crawlRoot}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGraph(t, g)
+}
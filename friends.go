@@ -0,0 +1,57 @@
+package SteamAPI
+
+// This file provides ISteamUser/GetFriendList, which lists a user's friends.
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// Type Friend holds one entry of a user's friend list.
+type Friend struct {
+	SteamID      SteamID `json:"steamid"`
+	Relationship string  `json:"relationship"` // Always "friend", so far
+	FriendSince  int64   `json:"friend_since"` // Unix time; 0 for old friendships
+}
+
+// Method Since returns the time the friendship began, or the zero time if
+// Steam does not know.
+func (f *Friend) Since() time.Time {
+	if f.FriendSince == 0 {
+		return time.Time{}
+	}
+	return time.Unix(f.FriendSince, 0)
+}
+
+// Function GetFriendList calls DefaultClient.GetFriendList.
+func GetFriendList(ctx context.Context, id SteamID) ([]Friend, error) {
+	return DefaultClient.GetFriendList(ctx, id)
+}
+
+// Method GetFriendList returns the friends of user 'id', using
+// ISteamUser/GetFriendList/v1.
+//
+// Steam answers 401 Unauthorized if the user's friend list is private, so
+// GetFriendList returns an error wrapping ErrPrivateProfile in that case.
+//
+func (c *Client) GetFriendList(ctx context.Context, id SteamID) ([]Friend, error) {
+	var resp struct {
+		FriendsList struct {
+			Friends []Friend `json:"friends"`
+		} `json:"friendslist"`
+	}
+	err := c.GetJSONValues(ctx, &resp, "friend list", "user "+id.String(),
+		"ISteamUser", "GetFriendList", 1, UseKey,
+		NewParams().SteamID("steamid", id).Set("relationship", "friend").Values())
+	if err != nil {
+		var webErr *WebError
+		if errors.As(err, &webErr) && webErr.StatusCode == http.StatusUnauthorized &&
+			webErr.BaseError == nil {
+			webErr.BaseError = ErrPrivateProfile
+		}
+		return nil, err
+	}
+	return resp.FriendsList.Friends, nil
+}
@@ -0,0 +1,151 @@
+package SteamAPI
+
+// This file writes news items (from GetNewsForApp) as RSS 2.0 or Atom feeds.
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Type FeedInfo describes a feed as a whole.
+type FeedInfo struct {
+	Title       string // Required
+	Link        string // URL of the web page the feed is about; required
+	Description string // Optional for Atom
+	SelfURL     string // Where the feed itself will be published; optional
+}
+
+// Method NewsItemURL returns the URL of a news item: its own URL, or else its
+// page on the Steam store.
+func (ni *NewsItem) NewsItemURL() string {
+	if ni.URL != "" {
+		return ni.URL
+	}
+	return fmt.Sprintf("https://store.steampowered.com/news/app/%d/view/%s",
+		ni.AppID, ni.GID)
+}
+
+/*================================== RSS 2.0 =================================*/
+
+type (
+	rssDocument struct {
+		XMLName xml.Name   `xml:"rss"`
+		Version string     `xml:"version,attr"`
+		Channel rssChannel `xml:"channel"`
+	}
+	rssChannel struct {
+		Title         string    `xml:"title"`
+		Link          string    `xml:"link"`
+		Description   string    `xml:"description"`
+		LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+		Items         []rssItem `xml:"item"`
+	}
+	rssItem struct {
+		Title       string  `xml:"title"`
+		Link        string  `xml:"link"`
+		Description string  `xml:"description"`
+		Author      string  `xml:"author,omitempty"`
+		Category    string  `xml:"category,omitempty"`
+		GUID        rssGUID `xml:"guid"`
+		PubDate     string  `xml:"pubDate"`
+	}
+	rssGUID struct {
+		IsPermaLink bool   `xml:"isPermaLink,attr"`
+		Value       string `xml:",chardata"`
+	}
+)
+
+// Function WriteNewsRSS writes items to w as an RSS 2.0 feed. Item contents
+// are written as they are; convert any BBCode first if that matters.
+func WriteNewsRSS(w io.Writer, info FeedInfo, items []NewsItem) error {
+	doc := rssDocument{Version: "2.0", Channel: rssChannel{
+		Title: info.Title, Link: info.Link, Description: info.Description}}
+	if len(items) > 0 {
+		doc.Channel.LastBuildDate = items[0].Time().UTC().Format(time.RFC1123Z)
+	}
+	for i := range items {
+		ni := &items[i]
+		doc.Channel.Items = append(doc.Channel.Items, rssItem{
+			Title: ni.Title, Link: ni.NewsItemURL(), Description: ni.Contents,
+			Author: ni.Author, Category: ni.FeedLabel,
+			GUID:    rssGUID{Value: "steam-news-" + ni.GID},
+			PubDate: ni.Time().UTC().Format(time.RFC1123Z)})
+	}
+	return writeXML(w, &doc)
+}
+
+/*=================================== Atom ===================================*/
+
+type (
+	atomFeed struct {
+		XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+		Title    string      `xml:"title"`
+		Subtitle string      `xml:"subtitle,omitempty"`
+		ID       string      `xml:"id"`
+		Updated  string      `xml:"updated"`
+		Links    []atomLink  `xml:"link"`
+		Entries  []atomEntry `xml:"entry"`
+	}
+	atomLink struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr,omitempty"`
+	}
+	atomEntry struct {
+		Title   string      `xml:"title"`
+		ID      string      `xml:"id"`
+		Updated string      `xml:"updated"`
+		Link    atomLink    `xml:"link"`
+		Author  *atomAuthor `xml:"author,omitempty"`
+		Content atomContent `xml:"content"`
+	}
+	atomAuthor struct {
+		Name string `xml:"name"`
+	}
+	atomContent struct {
+		Type  string `xml:"type,attr"`
+		Value string `xml:",chardata"`
+	}
+)
+
+// Function WriteNewsAtom writes items to w as an Atom feed. Item contents are
+// written as HTML; convert any BBCode first if that matters.
+func WriteNewsAtom(w io.Writer, info FeedInfo, items []NewsItem) error {
+	feed := atomFeed{Title: info.Title, Subtitle: info.Description,
+		ID: info.Link, Links: []atomLink{{Href: info.Link, Rel: "alternate"}}}
+	if info.SelfURL != "" {
+		feed.ID = info.SelfURL
+		feed.Links = append(feed.Links, atomLink{Href: info.SelfURL, Rel: "self"})
+	}
+	feed.Updated = time.Now().UTC().Format(time.RFC3339)
+	if len(items) > 0 {
+		feed.Updated = items[0].Time().UTC().Format(time.RFC3339)
+	}
+	for i := range items {
+		ni := &items[i]
+		entry := atomEntry{Title: ni.Title, ID: ni.NewsItemURL(),
+			Updated: ni.Time().UTC().Format(time.RFC3339),
+			Link:    atomLink{Href: ni.NewsItemURL(), Rel: "alternate"},
+			Content: atomContent{Type: "html", Value: ni.Contents}}
+		if ni.Author != "" {
+			entry.Author = &atomAuthor{Name: ni.Author}
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+	return writeXML(w, &feed)
+}
+
+func writeXML(w io.Writer, doc interface{}) error {
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	err = enc.Encode(doc)
+	if err == nil {
+		_, err = io.WriteString(w, "\n")
+	}
+	return err
+}
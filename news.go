@@ -0,0 +1,137 @@
+package SteamAPI
+
+// This file provides ISteamNews/GetNewsForApp, which returns news items (patch
+// notes, announcements and articles from external feeds) for one app.
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Type NewsItem holds one news item, as returned by ISteamNews/GetNewsForApp/v2.
+// Contents is usually in Steam's BBCode dialect for items from Steam's own
+// feeds, and HTML for items from external ones.
+//
+type NewsItem struct {
+	GID           string      `json:"gid"` // Unique ID
+	Title         string      `json:"title"`
+	URL           string      `json:"url"`
+	IsExternalURL bool        `json:"is_external_url"`
+	Author        string      `json:"author"`
+	Contents      string      `json:"contents"`
+	FeedLabel     string      `json:"feedlabel"`
+	Date          int64       `json:"date"` // Unix time
+	FeedName      string      `json:"feedname"`
+	FeedType      int         `json:"feed_type"` // 1 for Steam community announcements
+	AppID         SteamItemID `json:"appid"`
+	Tags          []string    `json:"tags,omitempty"`
+}
+
+// Method Time returns the date of the item as a time.Time.
+func (ni *NewsItem) Time() time.Time { return time.Unix(ni.Date, 0) }
+
+// Type NewsOptions holds the optional parameters for GetNewsForApp.
+type NewsOptions struct {
+	MaxItems  int       // How many items to return in total; 0 means 20
+	PageSize  int       // How many items to ask for per call; 0 means 100
+	MaxLength int       // Truncate contents to this many characters; 0 means don't
+	EndDate   time.Time // Only return items from before this; zero means now
+	Feeds     []string  // Only return items from these feeds (by FeedName)
+}
+
+const (
+	defaultNewsItems    = 20
+	defaultNewsPageSize = 100
+)
+
+// Function GetNewsForApp calls DefaultClient.GetNewsForApp.
+func GetNewsForApp(ctx context.Context, app SteamItemID, opts *NewsOptions,
+) ([]NewsItem, error) {
+	return DefaultClient.GetNewsForApp(ctx, app, opts)
+}
+
+// Method GetNewsForApp returns the news items for an app, newest first, using
+// ISteamNews/GetNewsForApp/v2 (which needs no key). Argument opts can be nil.
+//
+// Steam returns at most PageSize items per call, so GetNewsForApp pages back
+// through older items (by asking for items up to the date of the oldest item
+// so far) until it has opts.MaxItems items or runs out. Since items can share
+// a date, pages can overlap; GetNewsForApp drops duplicates by GID.
+//
+func (c *Client) GetNewsForApp(ctx context.Context, app SteamItemID,
+	opts *NewsOptions,
+) ([]NewsItem, error) {
+	if opts == nil {
+		opts = &NewsOptions{}
+	}
+	maxItems, pageSize := opts.MaxItems, opts.PageSize
+	if maxItems <= 0 {
+		maxItems = defaultNewsItems
+	}
+	if pageSize <= 0 {
+		pageSize = defaultNewsPageSize
+	}
+	if pageSize > maxItems {
+		pageSize = maxItems
+	}
+	params := NewParams().App("appid", app).Int("count", int64(pageSize))
+	if opts.MaxLength > 0 {
+		params.Int("maxlength", int64(opts.MaxLength))
+	}
+	if len(opts.Feeds) > 0 {
+		params.Set("feeds", strings.Join(opts.Feeds, ","))
+	}
+	if !opts.EndDate.IsZero() {
+		params.Int("enddate", opts.EndDate.Unix())
+	}
+
+	var items []NewsItem
+	seen := make(map[string]bool)
+	for len(items) < maxItems {
+		var resp struct {
+			AppNews struct {
+				NewsItems []NewsItem `json:"newsitems"`
+			} `json:"appnews"`
+		}
+		err := c.GetJSONValues(ctx, &resp, "news", "app "+app.String(),
+			"ISteamNews", "GetNewsForApp", 2, UseHTTPS, params.Values())
+		if err != nil {
+			return nil, err
+		}
+		page := resp.AppNews.NewsItems
+		added := 0
+		for _, ni := range page {
+			if !seen[ni.GID] && len(items) < maxItems {
+				seen[ni.GID] = true
+				items = append(items, ni)
+				added++
+			}
+		}
+		if added == 0 || len(page) < pageSize {
+			break
+		}
+		params.Int("enddate", page[len(page)-1].Date)
+	}
+	return items, nil
+}
+
+// Function MergeNews combines lists of news items (such as those for several
+// apps) into one list, newest first, without duplicates.
+func MergeNews(lists ...[]NewsItem) []NewsItem {
+	var merged []NewsItem
+	seen := make(map[string]bool)
+	for _, list := range lists {
+		for _, ni := range list {
+			if !seen[ni.GID] {
+				seen[ni.GID] = true
+				merged = append(merged, ni)
+			}
+		}
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Date > merged[j].Date
+	})
+	return merged
+}
@@ -0,0 +1,140 @@
+package SteamAPI
+
+// This file provides IPlayerService/GetOwnedGames and GetRecentlyPlayedGames,
+// which list the games in a user's library and how long the user has played
+// them.
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrPrivateProfile is wrapped by errors for calls about users whose profiles
+// (or the relevant parts of them) are private, for which Steam returns an empty
+// response rather than an HTTP error.
+var ErrPrivateProfile = errors.New("Steam profile is private")
+
+// Type GamePlaytime holds what Steam reports about one game in a user's
+// library. Playtimes are in minutes.
+type GamePlaytime struct {
+	AppID                    SteamItemID `json:"appid"`
+	Name                     string      `json:"name"`         // Only if asked for, or filled in
+	ImgIconURL               string      `json:"img_icon_url"` // Hash; see IconURL
+	HasCommunityVisibleStats bool        `json:"has_community_visible_stats"`
+	PlaytimeForever          int         `json:"playtime_forever"`
+	Playtime2Weeks           int         `json:"playtime_2weeks"`
+	PlaytimeWindowsForever   int         `json:"playtime_windows_forever"`
+	PlaytimeMacForever       int         `json:"playtime_mac_forever"`
+	PlaytimeLinuxForever     int         `json:"playtime_linux_forever"`
+	LastPlayed               int64       `json:"rtime_last_played"` // Unix time
+}
+
+// Method IconURL returns the URL of the game's icon, or "" if Steam did not
+// supply one.
+func (gp *GamePlaytime) IconURL() string {
+	if gp.ImgIconURL == "" {
+		return ""
+	}
+	return "https://media.steampowered.com/steamcommunity/public/images/apps/" +
+		gp.AppID.String() + "/" + gp.ImgIconURL + ".jpg"
+}
+
+// Type GameLibrary maps app IDs to playtime records.
+type GameLibrary map[SteamItemID]GamePlaytime
+
+// Type OwnedGamesOptions holds the optional parameters for GetOwnedGames.
+type OwnedGamesOptions struct {
+	IncludeAppInfo         bool          // Include names and icons
+	IncludePlayedFreeGames bool          // Include free games the user has played
+	AppIDsFilter           []SteamItemID // If not empty, only these games
+}
+
+// Function GetOwnedGames calls DefaultClient.GetOwnedGames.
+func GetOwnedGames(ctx context.Context, id SteamID, opts *OwnedGamesOptions,
+) (GameLibrary, error) {
+	return DefaultClient.GetOwnedGames(ctx, id, opts)
+}
+
+// Method GetOwnedGames returns the games owned by user 'id', using
+// IPlayerService/GetOwnedGames/v1. Argument opts can be nil.
+//
+// If the user's game details are private, GetOwnedGames returns an error which
+// wraps ErrPrivateProfile, rather than an empty library.
+//
+func (c *Client) GetOwnedGames(ctx context.Context, id SteamID,
+	opts *OwnedGamesOptions,
+) (GameLibrary, error) {
+	params := NewParams().SteamID("steamid", id)
+	if opts != nil {
+		if opts.IncludeAppInfo {
+			params.Bool("include_appinfo", true)
+		}
+		if opts.IncludePlayedFreeGames {
+			params.Bool("include_played_free_games", true)
+		}
+		if len(opts.AppIDsFilter) > 0 {
+			params.Apps("appids_filter", opts.AppIDsFilter...)
+		}
+	}
+	var resp struct {
+		Response struct {
+			GameCount *int           `json:"game_count"`
+			Games     []GamePlaytime `json:"games"`
+		} `json:"response"`
+	}
+	err := c.GetJSONValues(ctx, &resp, "owned games", "user "+id.String(),
+		"IPlayerService", "GetOwnedGames", 1, UseKey, params.Values())
+	if err != nil {
+		return nil, err
+	} else if resp.Response.GameCount == nil {
+		return nil, &WebError{Action: "get", What: "owned games",
+			Who: "user " + id.String(), BaseError: ErrPrivateProfile}
+	}
+	return newGameLibrary(resp.Response.Games), nil
+}
+
+// Function GetRecentlyPlayedGames calls DefaultClient.GetRecentlyPlayedGames.
+func GetRecentlyPlayedGames(ctx context.Context, id SteamID, count int,
+) (GameLibrary, error) {
+	return DefaultClient.GetRecentlyPlayedGames(ctx, id, count)
+}
+
+// Method GetRecentlyPlayedGames returns the games user 'id' has played in the
+// last two weeks (at most 'count' of them, unless count is 0), using
+// IPlayerService/GetRecentlyPlayedGames/v1.
+//
+// If the user's game details are private, it returns an error which wraps
+// ErrPrivateProfile.
+//
+func (c *Client) GetRecentlyPlayedGames(ctx context.Context, id SteamID,
+	count int,
+) (GameLibrary, error) {
+	params := NewParams().SteamID("steamid", id)
+	if count > 0 {
+		params.Int("count", int64(count))
+	}
+	var resp struct {
+		Response struct {
+			TotalCount *int           `json:"total_count"`
+			Games      []GamePlaytime `json:"games"`
+		} `json:"response"`
+	}
+	err := c.GetJSONValues(ctx, &resp, "recently played games",
+		"user "+id.String(),
+		"IPlayerService", "GetRecentlyPlayedGames", 1, UseKey, params.Values())
+	if err != nil {
+		return nil, err
+	} else if resp.Response.TotalCount == nil {
+		return nil, &WebError{Action: "get", What: "recently played games",
+			Who: "user " + id.String(), BaseError: ErrPrivateProfile}
+	}
+	return newGameLibrary(resp.Response.Games), nil
+}
+
+func newGameLibrary(games []GamePlaytime) GameLibrary {
+	lib := make(GameLibrary, len(games))
+	for _, g := range games {
+		lib[g.AppID] = g
+	}
+	return lib
+}
@@ -0,0 +1,106 @@
+package SteamAPI
+
+// This file helps build the query parameters for Web API calls.
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// Type Params holds the parameters for a Web API call. Its methods add typed
+// values in the forms Steam expects and return the Params, so calls can be
+// chained:
+//	p := SteamAPI.NewParams().App("appid", 440).Bool("include_appinfo", true)
+//	err := SteamAPI.GetJSONValues(ctx, &out, "...", "", iface, method, 1,
+//		SteamAPI.UseKey, p.Values())
+//
+type Params url.Values
+
+// Function NewParams returns an empty Params.
+func NewParams() Params { return Params{} }
+
+// Method Values returns p as a url.Values.
+func (p Params) Values() url.Values { return url.Values(p) }
+
+// Method Set sets parameter name to value, replacing any previous values.
+func (p Params) Set(name, value string) Params {
+	url.Values(p).Set(name, value)
+	return p
+}
+
+// Method Int sets parameter name to a signed integer.
+func (p Params) Int(name string, n int64) Params {
+	return p.Set(name, strconv.FormatInt(n, 10))
+}
+
+// Method Uint sets parameter name to an unsigned integer.
+func (p Params) Uint(name string, n uint64) Params {
+	return p.Set(name, strconv.FormatUint(n, 10))
+}
+
+// Method Bool sets parameter name to "true" or "false".
+func (p Params) Bool(name string, b bool) Params {
+	return p.Set(name, strconv.FormatBool(b))
+}
+
+// Method App sets parameter name to an app ID.
+func (p Params) App(name string, id SteamItemID) Params {
+	return p.Uint(name, uint64(id))
+}
+
+// Method SteamID sets parameter name to a SteamID, as a 64-bit number.
+func (p Params) SteamID(name string, id SteamID) Params {
+	return p.Uint(name, uint64(id))
+}
+
+// Method Array sets the array parameter name to values, using the form Steam
+// expects for arrays: name[0]=v0, name[1]=v1 and so on. It also removes any
+// elements left over from an earlier, longer array.
+//
+func (p Params) Array(name string, values ...string) Params {
+	for i := 0; ; i++ {
+		elemName := fmt.Sprintf("%s[%d]", name, i)
+		if i < len(values) {
+			p.Set(elemName, values[i])
+		} else if _, present := p[elemName]; present {
+			delete(p, elemName)
+		} else {
+			break
+		}
+	}
+	return p
+}
+
+// Method Apps sets the array parameter name to a list of app IDs.
+func (p Params) Apps(name string, ids ...SteamItemID) Params {
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = strconv.FormatUint(uint64(id), 10)
+	}
+	return p.Array(name, values...)
+}
+
+// pairsToValues turns alternating names and values into a url.Values.
+func pairsToValues(params []string) (url.Values, error) {
+	if len(params)%2 != 0 {
+		return nil, &ParamError{Name: params[len(params)-1],
+			Problem: "has no value"}
+	}
+	values := make(url.Values, len(params)/2)
+	for i := 0; i < len(params); i += 2 {
+		values.Add(params[i], params[i+1])
+	}
+	return values, nil
+}
+
+// Type ParamError represents a problem with the parameters for a call.
+type ParamError struct {
+	Name    string // The parameter concerned
+	Problem string // What is wrong with it
+}
+
+func (e *ParamError) Error() string {
+	return fmt.Sprintf("bad Steam Web API call: parameter %q %s",
+		e.Name, e.Problem)
+}
@@ -0,0 +1,64 @@
+package SteamAPI
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParamsArray(t *testing.T) {
+	p := NewParams().Array("steamids", "1", "2", "3").Set("format", "json")
+	if got, want := p.Values().Encode(),
+		"format=json&steamids%5B0%5D=1&steamids%5B1%5D=2&steamids%5B2%5D=3"; got != want {
+		t.Errorf("Encode() = %q, want %q", got, want)
+	}
+
+	// A shorter array replaces all of a longer one.
+	p.Array("steamids", "9")
+	if got, want := p.Values().Encode(), "format=json&steamids%5B0%5D=9"; got != want {
+		t.Errorf("after a shorter Array, Encode() = %q, want %q", got, want)
+	}
+
+	p.Array("steamids")
+	if got, want := p.Values().Encode(), "format=json"; got != want {
+		t.Errorf("after an empty Array, Encode() = %q, want %q", got, want)
+	}
+}
+
+func TestParamsTyped(t *testing.T) {
+	p := NewParams().App("appid", 440).SteamID("steamid", gabeN).
+		Bool("include_appinfo", true).Int("count", -1).Uint("start", 7).
+		Apps("appids_filter", 10, 20)
+	want := map[string]string{
+		"appid":            "440",
+		"steamid":          "76561197960287930",
+		"include_appinfo":  "true",
+		"count":            "-1",
+		"start":            "7",
+		"appids_filter[0]": "10",
+		"appids_filter[1]": "20",
+	}
+	if len(p) != len(want) {
+		t.Errorf("got %d parameters, want %d: %v", len(p), len(want), p)
+	}
+	for name, value := range want {
+		if got := p.Values().Get(name); got != value {
+			t.Errorf("%s = %q, want %q", name, got, value)
+		}
+	}
+}
+
+func TestPairsToValues(t *testing.T) {
+	values, err := pairsToValues([]string{"a", "1", "b", "2", "a", "3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := values.Encode(), "a=1&a=3&b=2"; got != want {
+		t.Errorf("Encode() = %q, want %q", got, want)
+	}
+
+	_, err = pairsToValues([]string{"a", "1", "b"})
+	var paramErr *ParamError
+	if !errors.As(err, &paramErr) || paramErr.Name != "b" {
+		t.Errorf("odd number of params: got %v, want a *ParamError for \"b\"", err)
+	}
+}
@@ -0,0 +1,85 @@
+package SteamAPI
+
+// This file provides ISteamUser/GetPlayerBans, which reports the VAC, game,
+// community and trade bans of up to 100 users per call.
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// maxBansPerCall is the most SteamIDs GetPlayerBans/v1 accepts.
+const maxBansPerCall = 100
+
+// Type PlayerBans holds the ban status of one user, as returned by
+// ISteamUser/GetPlayerBans/v1.
+type PlayerBans struct {
+	SteamID          SteamID `json:"SteamId"`
+	CommunityBanned  bool    `json:"CommunityBanned"`
+	VACBanned        bool    `json:"VACBanned"`
+	NumberOfVACBans  int     `json:"NumberOfVACBans"`
+	DaysSinceLastBan int     `json:"DaysSinceLastBan"` // 0 if never banned
+	NumberOfGameBans int     `json:"NumberOfGameBans"`
+	EconomyBan       string  `json:"EconomyBan"` // "none", "probation" or "banned"
+}
+
+// Method IsBanned reports whether the user has any sort of ban.
+func (pb *PlayerBans) IsBanned() bool {
+	return pb.CommunityBanned || pb.VACBanned || pb.NumberOfVACBans > 0 ||
+		pb.NumberOfGameBans > 0 || (pb.EconomyBan != "" && pb.EconomyBan != "none")
+}
+
+// Type PlayerBansResult holds the result of GetPlayerBans.
+type PlayerBansResult struct {
+	Bans    map[SteamID]PlayerBans
+	Missing []SteamID // SteamIDs which Steam returned nothing for
+}
+
+// Function GetPlayerBans calls DefaultClient.GetPlayerBans.
+func GetPlayerBans(ctx context.Context, ids ...SteamID) (*PlayerBansResult, error) {
+	return DefaultClient.GetPlayerBans(ctx, ids...)
+}
+
+// Method GetPlayerBans returns the ban status of the users with the given
+// SteamIDs, ignoring any duplicates.
+//
+// Steam accepts at most 100 SteamIDs per call, so GetPlayerBans splits longer
+// lists into batches, sending up to c.MaxConcurrency requests at once. If any
+// request fails, it returns that error (and no results).
+//
+func (c *Client) GetPlayerBans(ctx context.Context, ids ...SteamID,
+) (*PlayerBansResult, error) {
+	ids = uniqueSteamIDs(ids)
+	var mu sync.Mutex
+	result := &PlayerBansResult{Bans: make(map[SteamID]PlayerBans, len(ids))}
+	err := c.forEachBatch(ctx, len(ids), maxBansPerCall,
+		func(ctx context.Context, lo, hi int) error {
+			var resp struct {
+				Players []PlayerBans `json:"players"`
+			}
+			err := c.GetJSONValues(ctx, &resp, "player bans",
+				fmt.Sprintf("%d users", hi-lo),
+				"ISteamUser", "GetPlayerBans", 1, UseKey,
+				NewParams().Set("steamids", joinSteamIDs(ids[lo:hi])).Values())
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			for _, pb := range resp.Players {
+				result.Bans[pb.SteamID] = pb
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		if _, ok := result.Bans[id]; !ok {
+			result.Missing = append(result.Missing, id)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,108 @@
+package SteamAPI
+
+// This file provides ISteamUser/GetPlayerSummaries, which returns the public
+// profile information of up to 100 users per call.
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// maxSummariesPerCall is the most SteamIDs GetPlayerSummaries/v2 accepts.
+const maxSummariesPerCall = 100
+
+// Type PlayerSummary holds the profile information for one user, as returned by
+// ISteamUser/GetPlayerSummaries/v2. Only the fields up to PersonaState are
+// available for private profiles; the others are left empty.
+//
+type PlayerSummary struct {
+	SteamID                  SteamID `json:"steamid"`
+	CommunityVisibilityState int     `json:"communityvisibilitystate"` // 3 = public
+	ProfileState             int     `json:"profilestate"`             // 1 = set up
+	PersonaName              string  `json:"personaname"`
+	ProfileURL               string  `json:"profileurl"`
+	Avatar                   string  `json:"avatar"`       // 32×32 image URL
+	AvatarMedium             string  `json:"avatarmedium"` // 64×64 image URL
+	AvatarFull               string  `json:"avatarfull"`   // 184×184 image URL
+	AvatarHash               string  `json:"avatarhash"`
+	LastLogoff               int64   `json:"lastlogoff"`   // Unix time
+	PersonaState             int     `json:"personastate"` // 0 = offline, 1 = online, ...
+	PersonaStateFlags        int     `json:"personastateflags"`
+	CommentPermission        int     `json:"commentpermission"`
+
+	RealName       string  `json:"realname"`
+	PrimaryClanID  SteamID `json:"primaryclanid"`
+	TimeCreated    int64   `json:"timecreated"` // Unix time
+	GameID         string  `json:"gameid"`      // What the user is playing now
+	GameServerIP   string  `json:"gameserverip"`
+	GameExtraInfo  string  `json:"gameextrainfo"`
+	LocCountryCode string  `json:"loccountrycode"`
+	LocStateCode   string  `json:"locstatecode"`
+	LocCityID      int     `json:"loccityid"`
+}
+
+// Method IsPublic reports whether the profile is visible to everyone.
+func (ps *PlayerSummary) IsPublic() bool { return ps.CommunityVisibilityState == 3 }
+
+// Type PlayerSummaries holds the result of GetPlayerSummaries.
+type PlayerSummaries struct {
+	Players []PlayerSummary // In the order of the SteamIDs requested
+	Missing []SteamID       // SteamIDs which Steam returned nothing for
+}
+
+// Function GetPlayerSummaries calls DefaultClient.GetPlayerSummaries.
+func GetPlayerSummaries(ctx context.Context, ids ...SteamID,
+) (*PlayerSummaries, error) {
+	return DefaultClient.GetPlayerSummaries(ctx, ids...)
+}
+
+// Method GetPlayerSummaries returns the profile information for the users with
+// the given SteamIDs, ignoring any duplicates.
+//
+// Steam accepts at most 100 SteamIDs per call, so GetPlayerSummaries splits
+// longer lists into batches, sending up to c.MaxConcurrency requests at once.
+// If any request fails, it returns that error (and no summaries).
+//
+func (c *Client) GetPlayerSummaries(ctx context.Context, ids ...SteamID,
+) (*PlayerSummaries, error) {
+	ids = uniqueSteamIDs(ids)
+	var (
+		mu    sync.Mutex
+		found = make(map[SteamID]PlayerSummary, len(ids))
+	)
+	err := c.forEachBatch(ctx, len(ids), maxSummariesPerCall,
+		func(ctx context.Context, lo, hi int) error {
+			var resp struct {
+				Response struct {
+					Players []PlayerSummary `json:"players"`
+				} `json:"response"`
+			}
+			err := c.GetJSONValues(ctx, &resp, "player summaries",
+				fmt.Sprintf("%d users", hi-lo),
+				"ISteamUser", "GetPlayerSummaries", 2, UseKey,
+				NewParams().Set("steamids", joinSteamIDs(ids[lo:hi])).Values())
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			for _, ps := range resp.Response.Players {
+				found[ps.SteamID] = ps
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PlayerSummaries{Players: make([]PlayerSummary, 0, len(found))}
+	for _, id := range ids {
+		if ps, ok := found[id]; ok {
+			result.Players = append(result.Players, ps)
+		} else {
+			result.Missing = append(result.Missing, id)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,269 @@
+package SteamAPI
+
+// This file keeps count of the calls made to Steam's Web API, so that programs
+// can stay within the limit of 100,000 calls per day set by the Steam Web API
+// Terms of Use.
+//
+// The counts live in files under CacheDirPath(), one per key per UTC day, so
+// several processes using the same key share one count. A lock file serialises
+// updates; each update rewrites the count file via a temporary file and a
+// rename, so readers never see a half-written count.
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DailyCallLimit is the number of calls per key per day allowed by the Steam
+// Web API Terms of Use.
+const DailyCallLimit = 100000
+
+// ErrQuotaExhausted is the error (or rather, errors.Is-compatible value) that
+// a Client with a Quota returns instead of sending a request which would go
+// over the daily budget.
+var ErrQuotaExhausted = errors.New("daily Steam Web API quota exhausted")
+
+// Type Quota counts Web API calls per key and per UTC day, refusing calls
+// which would go over a budget. Set the Quota field of a Client to use one.
+//
+// The zero value uses the directory "quota" under CacheDirPath() and a budget
+// of DailyCallLimit. A Quota is safe for concurrent use.
+//
+type Quota struct {
+	Dir    string // Where to keep the count files; "" means the default
+	Budget int    // Maximum calls per key per day; 0 means DailyCallLimit
+
+	mu sync.Mutex
+}
+
+// Function NewQuota returns a Quota using the default directory and the given
+// daily budget (or DailyCallLimit if budget is zero).
+func NewQuota(budget int) *Quota {
+	return &Quota{Budget: budget}
+}
+
+// Type QuotaUsage reports the calls counted against one key on one UTC day.
+type QuotaUsage struct {
+	Day      string         // The UTC date, as YYYY-MM-DD
+	Total    int            // The number of calls
+	ByMethod map[string]int // The number of calls per "Interface/Method"
+}
+
+// Type QuotaError is the error returned when a call would exceed the budget.
+// It matches ErrQuotaExhausted for errors.Is.
+type QuotaError struct {
+	KeyID  string // Identifies the key without revealing it
+	Day    string // The UTC date, as YYYY-MM-DD
+	Budget int    // The budget in force
+	Used   int    // Calls already counted for KeyID on Day
+}
+
+func (e *QuotaError) Error() string {
+	return fmt.Sprintf("%s: %d of %d calls used for key %s on %s",
+		ErrQuotaExhausted, e.Used, e.Budget, e.KeyID, e.Day)
+}
+
+func (e *QuotaError) Is(target error) bool { return target == ErrQuotaExhausted }
+
+/*================================= Counting =================================*/
+
+// Method Spend counts one call to iface/method using key (which may be "" for
+// calls without a key), or returns a *QuotaError without counting anything if
+// that call would go over q's budget.
+//
+func (q *Quota) Spend(key, iface, method string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	keyID, day := quotaKeyID(key), quotaDay(time.Now())
+	quotaDir, err := q.dir()
+	if err != nil {
+		return err
+	}
+	dir := filepath.Join(quotaDir, keyID)
+	if err := makeCacheDir(dir); err != nil {
+		return err
+	}
+	unlock, err := lockQuotaDir(dir)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	path := filepath.Join(dir, day+".json")
+	usage, err := readQuotaUsage(path, day)
+	if err != nil {
+		return err
+	}
+	if usage.Total >= q.budget() {
+		return &QuotaError{KeyID: keyID, Day: day,
+			Budget: q.budget(), Used: usage.Total}
+	}
+	usage.Total++
+	usage.ByMethod[iface+"/"+method]++
+	return writeQuotaUsage(path, usage)
+}
+
+// Method Usage returns the calls counted against key on the UTC day containing
+// t.
+func (q *Quota) Usage(key string, t time.Time) (*QuotaUsage, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	day := quotaDay(t)
+	dir, err := q.dir()
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, quotaKeyID(key), day+".json")
+	return readQuotaUsage(path, day)
+}
+
+// Method Remaining returns how many more calls q will allow for key today.
+func (q *Quota) Remaining(key string) (int, error) {
+	usage, err := q.Usage(key, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	if left := q.budget() - usage.Total; left > 0 {
+		return left, nil
+	}
+	return 0, nil
+}
+
+// Method Report returns a human-readable summary of u, with one line per
+// method, busiest first.
+func (u *QuotaUsage) Report() string {
+	methods := make([]string, 0, len(u.ByMethod))
+	for m := range u.ByMethod {
+		methods = append(methods, m)
+	}
+	sort.Slice(methods, func(i, j int) bool {
+		ci, cj := u.ByMethod[methods[i]], u.ByMethod[methods[j]]
+		return ci > cj || (ci == cj && methods[i] < methods[j])
+	})
+	buf := new(strings.Builder)
+	fmt.Fprintf(buf, "%s: %d calls\n", u.Day, u.Total)
+	for _, m := range methods {
+		fmt.Fprintf(buf, "%8d  %s\n", u.ByMethod[m], m)
+	}
+	return buf.String()
+}
+
+func (q *Quota) dir() (string, error) {
+	if q.Dir != "" {
+		return q.Dir, nil
+	}
+	dir, err := cacheDirPath()
+	return filepath.Join(dir, "quota"), err
+}
+
+func (q *Quota) budget() int {
+	if q.Budget > 0 {
+		return q.Budget
+	}
+	return DailyCallLimit
+}
+
+// quotaKeyID returns a name for a key which is safe to use in file names and
+// error messages.
+func quotaKeyID(key string) string {
+	if key == "" {
+		return "no-key"
+	}
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:6])
+}
+
+func quotaDay(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
+
+/*================================== Files ===================================*/
+
+const (
+	quotaLockName  = ".lock"
+	quotaLockWait  = 10 * time.Millisecond
+	quotaLockLimit = 5 * time.Second
+	quotaLockStale = 30 * time.Second
+)
+
+// lockQuotaDir creates a lock file in dir, waiting for any other process to
+// remove its lock first, and returns a function which removes the lock.
+func lockQuotaDir(dir string) (func(), error) {
+	path := filepath.Join(dir, quotaLockName)
+	deadline := time.Now().Add(quotaLockLimit)
+	for {
+		fh, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			fh.Close()
+			return func() { os.Remove(path) }, nil
+		} else if !os.IsExist(err) {
+			return nil, &CacheError{Action: "create lock file", Path: path,
+				BaseError: err}
+		}
+		// A lock left behind by a crashed process should not block us forever.
+		if fi, err := os.Stat(path); err == nil &&
+			time.Since(fi.ModTime()) > quotaLockStale {
+			removeStaleLock(path, fi)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, &CacheError{Action: "lock", Path: path,
+				Problem: "timed out waiting for another process"}
+		}
+		time.Sleep(quotaLockWait)
+	}
+}
+
+// removeStaleLock removes the lock file at path, which was found to be stale
+// when stat'd as fi. Another process may have replaced the stale lock with its
+// own since then, so rather than removing path blindly, removeStaleLock moves
+// it aside, and puts it back if it turns out not to be the stale lock.
+func removeStaleLock(path string, fi os.FileInfo) {
+	aside := fmt.Sprintf("%s.stale-%d-%d", path, os.Getpid(), time.Now().UnixNano())
+	if os.Rename(path, aside) != nil {
+		return // Someone else got there first
+	}
+	if movedFi, err := os.Stat(aside); err == nil && !os.SameFile(fi, movedFi) {
+		// A fresh lock: restore it, unless yet another lock has appeared
+		os.Link(aside, path)
+	}
+	os.Remove(aside)
+}
+
+func readQuotaUsage(path, day string) (*QuotaUsage, error) {
+	usage := &QuotaUsage{Day: day, ByMethod: make(map[string]int)}
+	contents, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return usage, nil
+	} else if err != nil {
+		return nil, &CacheError{Action: "read", Path: path, BaseError: err}
+	}
+	err = json.Unmarshal(contents, usage)
+	if err != nil {
+		return nil, &CacheError{Action: "parse", Path: path, BaseError: err}
+	}
+	if usage.ByMethod == nil {
+		usage.ByMethod = make(map[string]int)
+	}
+	return usage, nil
+}
+
+func writeQuotaUsage(path string, usage *QuotaUsage) error {
+	contents, err := json.Marshal(usage)
+	if err != nil {
+		return &CacheError{Action: "encode", Path: path, BaseError: err}
+	}
+	return writeFileAtomically(path, contents)
+}
@@ -0,0 +1,162 @@
+package SteamAPI
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTestQuota returns a Quota with the given budget which keeps its counts in
+// a temporary directory. Call the function it returns when done.
+func newTestQuota(t *testing.T, budget int) (*Quota, func()) {
+	dir, err := ioutil.TempDir("", "quota")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Quota{Dir: dir, Budget: budget}, func() { os.RemoveAll(dir) }
+}
+
+func usage(t *testing.T, q *Quota, key string, when time.Time) *QuotaUsage {
+	t.Helper()
+	u, err := q.Usage(key, when)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
+
+func TestQuotaBudget(t *testing.T) {
+	q, cleanup := newTestQuota(t, 3)
+	defer cleanup()
+
+	for i := 0; i < 3; i++ {
+		if err := q.Spend("k", "ITest", "Count"); err != nil {
+			t.Fatalf("call %d: %v", i+1, err)
+		}
+	}
+	err := q.Spend("k", "ITest", "Count")
+	var quotaErr *QuotaError
+	if !errors.As(err, &quotaErr) || !errors.Is(err, ErrQuotaExhausted) {
+		t.Fatalf("call 4: got %v, want a *QuotaError", err)
+	}
+	if quotaErr.Used != 3 || quotaErr.Budget != 3 || quotaErr.KeyID != quotaKeyID("k") {
+		t.Errorf("QuotaError = %+v, want 3 of 3 used for %s", quotaErr, quotaKeyID("k"))
+	}
+	if err := q.Spend("other key", "ITest", "Count"); err != nil {
+		t.Errorf("another key's first call: %v", err)
+	}
+	if left, err := q.Remaining("k"); err != nil || left != 0 {
+		t.Errorf("Remaining = %d, %v; want 0, nil", left, err)
+	}
+	u := usage(t, q, "k", time.Now())
+	if u.Total != 3 || u.ByMethod["ITest/Count"] != 3 {
+		t.Errorf("Usage = %+v, want 3 calls to ITest/Count", u)
+	}
+}
+
+func TestQuotaNewDay(t *testing.T) {
+	q, cleanup := newTestQuota(t, 2)
+	defer cleanup()
+
+	// Use up yesterday's budget.
+	yesterday := time.Now().Add(-24 * time.Hour)
+	dir := filepath.Join(q.Dir, quotaKeyID("k"))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	err := writeQuotaUsage(filepath.Join(dir, quotaDay(yesterday)+".json"),
+		&QuotaUsage{Day: quotaDay(yesterday), Total: 2,
+			ByMethod: map[string]int{"ITest/Count": 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := q.Spend("k", "ITest", "Count"); err != nil {
+		t.Fatalf("first call of a new day: %v", err)
+	}
+	if u := usage(t, q, "k", time.Now()); u.Total != 1 {
+		t.Errorf("today's Total = %d, want 1", u.Total)
+	}
+	if u := usage(t, q, "k", yesterday); u.Total != 2 {
+		t.Errorf("yesterday's Total = %d, want 2", u.Total)
+	}
+}
+
+func TestQuotaSharedBetweenQuotas(t *testing.T) {
+	q, cleanup := newTestQuota(t, 0)
+	defer cleanup()
+	// A second Quota stands in for another process using the same directory.
+	q2 := &Quota{Dir: q.Dir}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 40)
+	for i := 0; i < 20; i++ {
+		for _, quota := range []*Quota{q, q2} {
+			wg.Add(1)
+			go func(quota *Quota) {
+				defer wg.Done()
+				errs <- quota.Spend("k", "ITest", "Count")
+			}(quota)
+		}
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if u := usage(t, q, "k", time.Now()); u.Total != 40 {
+		t.Errorf("Total after 40 concurrent calls = %d, want 40", u.Total)
+	}
+}
+
+func TestQuotaStaleLock(t *testing.T) {
+	q, cleanup := newTestQuota(t, 0)
+	defer cleanup()
+
+	dir := filepath.Join(q.Dir, quotaKeyID("k"))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	lock := filepath.Join(dir, quotaLockName)
+	if err := ioutil.WriteFile(lock, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * quotaLockStale)
+	if err := os.Chtimes(lock, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	if err := q.Spend("k", "ITest", "Count"); err != nil {
+		t.Fatalf("with a stale lock: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed >= quotaLockLimit {
+		t.Errorf("took %s to get past a stale lock", elapsed)
+	}
+	if _, err := os.Stat(lock); !os.IsNotExist(err) {
+		t.Errorf("lock file still there after Spend (stat: %v)", err)
+	}
+}
+
+func TestClientQuotaCountsRetries(t *testing.T) {
+	fs := &flakyServer{status: http.StatusServiceUnavailable, failures: 2}
+	c, cleanupServer := newFlakyClient(fs)
+	defer cleanupServer()
+	q, cleanup := newTestQuota(t, 0)
+	defer cleanup()
+	c.Quota = q
+
+	if err := getEmpty(c); err != nil {
+		t.Fatal(err)
+	}
+	if u := usage(t, q, "", time.Now()); u.Total != 3 || u.ByMethod["ITest/Flaky"] != 3 {
+		t.Errorf("Usage after 2 retries = %+v, want 3 calls to ITest/Flaky", u)
+	}
+}
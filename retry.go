@@ -0,0 +1,133 @@
+package SteamAPI
+
+// This file decides when and how long to wait before repeating a failed
+// request. Steam's servers answer with 429 (Too Many Requests) when they want
+// callers to slow down, and fairly often with 5xx codes when busy, so programs
+// making many calls do better to retry those than to give up.
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Type RetryPolicy says how a Client retries GET requests which fail with a
+// transport error or with HTTP status 429, 500, 502, 503 or 504.
+//
+// The delay before retry N (counting from 1) is a random duration between
+// zero and BaseDelay×2^(N-1), capped at MaxDelay ("full jitter"), except that
+// a Retry-After header in the response overrides it (but is still capped at
+// MaxDelay). A Client stops retrying after MaxAttempts attempts, or when the
+// next delay would take the total time since the first attempt past
+// MaxElapsed.
+//
+type RetryPolicy struct {
+	MaxAttempts int           // Attempts in total, including the first
+	BaseDelay   time.Duration // Upper limit for the first delay
+	MaxDelay    time.Duration // Upper limit for any one delay; 0 means none
+	MaxElapsed  time.Duration // Upper limit for the total time; 0 means none
+}
+
+// DefaultRetryPolicy is the policy used for calls with UseRetries in their
+// flags by Clients whose RetryPolicy field is nil.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 5,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    30 * time.Second,
+	MaxElapsed:  2 * time.Minute,
+}
+
+// retryPolicy returns the policy for a call with the given flags, or nil if
+// the call should not be retried.
+func (c *Client) retryPolicy(flags int) *RetryPolicy {
+	if c.RetryPolicy != nil {
+		return c.RetryPolicy
+	} else if (flags|c.Flags)&UseRetries != 0 {
+		return &DefaultRetryPolicy
+	}
+	return nil
+}
+
+// isRetryableStatus reports whether an HTTP status code suggests that the same
+// request might succeed later.
+func isRetryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// delay returns how long to wait before retrying after 'attempt' attempts,
+// given the last response (which may be nil).
+func (p *RetryPolicy) delay(attempt int, response *http.Response) time.Duration {
+	if response != nil {
+		if d, ok := retryAfter(response.Header.Get("Retry-After")); ok {
+			if p.MaxDelay > 0 && d > p.MaxDelay {
+				d = p.MaxDelay
+			}
+			return d
+		}
+	}
+	limit := p.BaseDelay
+	for i := 1; i < attempt && limit > 0; i++ {
+		if limit > math.MaxInt64/2 {
+			limit = math.MaxInt64
+			break
+		}
+		limit *= 2
+		if p.MaxDelay > 0 && limit >= p.MaxDelay {
+			break
+		}
+	}
+	if p.MaxDelay > 0 && limit > p.MaxDelay {
+		limit = p.MaxDelay
+	}
+	if limit <= 0 {
+		return 0
+	}
+	n := int64(limit)
+	if n < math.MaxInt64 {
+		n++ // So limit itself is possible
+	}
+	return time.Duration(rand.Int63n(n))
+}
+
+// retryAfter parses the value of a Retry-After header, which can be a number
+// of seconds or an HTTP date.
+func retryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+// sleepContext waits for d, or until ctx is done, and returns ctx.Err() in the
+// latter case.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,175 @@
+package SteamAPI
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRetryDelayLimits(t *testing.T) {
+	p := &RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	for attempt, limit := range []time.Duration{0, 100 * time.Millisecond,
+		200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond,
+		time.Second, time.Second} {
+		if attempt == 0 {
+			continue
+		}
+		for i := 0; i < 100; i++ {
+			if d := p.delay(attempt, nil); d < 0 || d > limit {
+				t.Fatalf("delay(%d) = %s, want 0 to %s", attempt, d, limit)
+			}
+		}
+	}
+
+	// Without MaxDelay, the delays keep growing, and do not overflow.
+	p.MaxDelay = 0
+	longest := time.Duration(0)
+	for i := 0; i < 100; i++ {
+		if d := p.delay(20, nil); d > longest {
+			longest = d
+		}
+	}
+	if longest <= time.Second {
+		t.Errorf("longest of 100 delay(20)s without MaxDelay = %s, want more", longest)
+	}
+	for _, attempt := range []int{63, 64, 1000} {
+		if d := p.delay(attempt, nil); d < 0 {
+			t.Errorf("delay(%d) without MaxDelay = %s", attempt, d)
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	p := &RetryPolicy{BaseDelay: time.Hour}
+	header := func(value string) *http.Response {
+		return &http.Response{Header: http.Header{"Retry-After": {value}}}
+	}
+	if d := p.delay(1, header("2")); d != 2*time.Second {
+		t.Errorf("delay with Retry-After: 2 = %s, want 2s", d)
+	}
+	past := time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat)
+	if d := p.delay(1, header(past)); d != 0 {
+		t.Errorf("delay with Retry-After in the past = %s, want 0", d)
+	}
+	future := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	if d := p.delay(1, header(future)); d < 58*time.Second || d > time.Minute {
+		t.Errorf("delay with Retry-After a minute from now = %s", d)
+	}
+	p.MaxDelay = time.Second
+	if d := p.delay(1, header("3600")); d != time.Second {
+		t.Errorf("delay with Retry-After: 3600 = %s, want MaxDelay (1s)", d)
+	}
+	if d := p.delay(1, header("soon")); d < 0 || d > time.Second {
+		t.Errorf("delay with Retry-After: soon = %s, want 0 to 1s", d)
+	}
+}
+
+// flakyServer is a stand-in for the Web API which answers the first 'failures'
+// requests with status (and any Retry-After header in retryAfter), then
+// answers with an empty JSON object.
+type flakyServer struct {
+	status     int
+	retryAfter string
+	failures   int
+	calls      int
+}
+
+func (fs *flakyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	fs.calls++
+	if fs.calls <= fs.failures {
+		if fs.retryAfter != "" {
+			w.Header().Set("Retry-After", fs.retryAfter)
+		}
+		http.Error(w, "busy", fs.status)
+		return
+	}
+	w.Write([]byte("{}"))
+}
+
+// newFlakyClient returns a Client which retries quickly and a server for it.
+// Call the function it returns when done.
+func newFlakyClient(fs *flakyServer) (*Client, func()) {
+	server := httptest.NewServer(fs)
+	return &Client{BaseURL: server.URL, Key: StaticKey("test"),
+		RetryPolicy: &RetryPolicy{MaxAttempts: 4, BaseDelay: time.Millisecond,
+			MaxDelay: 10 * time.Millisecond}}, server.Close
+}
+
+func getEmpty(c *Client) error {
+	var v struct{}
+	return c.GetJSONContext(context.Background(), &v, "nothing", "",
+		"ITest", "Flaky", 1, 0)
+}
+
+func TestClientRetries(t *testing.T) {
+	fs := &flakyServer{status: http.StatusServiceUnavailable, failures: 3}
+	c, cleanup := newFlakyClient(fs)
+	defer cleanup()
+
+	if err := getEmpty(c); err != nil {
+		t.Fatalf("after 3 failures: %v", err)
+	}
+	if fs.calls != 4 {
+		t.Errorf("server got %d calls, want 4", fs.calls)
+	}
+
+	fs.calls, fs.failures = 0, 10
+	err := getEmpty(c)
+	var webErr *WebError
+	if !errors.As(err, &webErr) || !errors.Is(err, ErrServerError) {
+		t.Fatalf("after too many failures: got %v, want a *WebError matching"+
+			" ErrServerError", err)
+	}
+	if webErr.Attempts != 4 || fs.calls != 4 {
+		t.Errorf("Attempts = %d, calls = %d; want 4, 4", webErr.Attempts, fs.calls)
+	}
+}
+
+func TestClientDoesNotRetryClientErrors(t *testing.T) {
+	fs := &flakyServer{status: http.StatusNotFound, failures: 1}
+	c, cleanup := newFlakyClient(fs)
+	defer cleanup()
+
+	if err := getEmpty(c); !errors.Is(err, ErrNotFound) {
+		t.Errorf("got %v, want an error matching ErrNotFound", err)
+	}
+	if fs.calls != 1 {
+		t.Errorf("server got %d calls for a 404, want 1", fs.calls)
+	}
+}
+
+func TestClientRetryAfterCappedAtMaxDelay(t *testing.T) {
+	fs := &flakyServer{status: http.StatusTooManyRequests, retryAfter: "3600",
+		failures: 1}
+	c, cleanup := newFlakyClient(fs)
+	defer cleanup()
+
+	start := time.Now()
+	if err := getEmpty(c); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("took %s, despite MaxDelay of %s", elapsed, c.RetryPolicy.MaxDelay)
+	}
+	if fs.calls != 2 {
+		t.Errorf("server got %d calls, want 2", fs.calls)
+	}
+}
+
+func TestClientRetryGivesUpWhenCancelled(t *testing.T) {
+	fs := &flakyServer{status: http.StatusBadGateway, failures: 10}
+	c, cleanup := newFlakyClient(fs)
+	defer cleanup()
+	c.RetryPolicy = &RetryPolicy{MaxAttempts: 10, BaseDelay: time.Hour}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	var v struct{}
+	err := c.GetJSONContext(ctx, &v, "nothing", "", "ITest", "Flaky", 1, 0)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, want an error matching context.DeadlineExceeded", err)
+	}
+}
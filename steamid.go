@@ -0,0 +1,306 @@
+package SteamAPI
+
+// This file provides the SteamID type, for the numeric IDs of Steam users (and
+// of other Steam accounts, such as groups and game servers).
+//
+// A 64-bit SteamID packs four fields together:
+//	bits 56-63	universe (1 for the public universe)
+//	bits 52-55	account type (1 for individual users, 7 for groups, ...)
+//	bits 32-51	instance (1 for individual users on desktop clients)
+//	bits  0-31	account ID
+// and people write SteamIDs in several forms, such as
+//	76561197960287930			the 64-bit number
+//	STEAM_0:0:11101				"Steam2" form, as used by older games
+//	[U:1:22202]				"Steam3" form
+//	22202					the bare 32-bit account ID
+//	https://steamcommunity.com/profiles/76561197960287930
+// See https://developer.valvesoftware.com/wiki/SteamID for details.
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Type SteamID holds the 64-bit numeric ID of a Steam account (usually a user).
+type SteamID uint64
+
+// NullSteamID64 is the zero value for a SteamID, which no account has.
+const NullSteamID64 = SteamID(0)
+
+// Type Universe identifies a Steam ‘universe’. Normal accounts belong to
+// UniversePublic.
+type Universe uint8
+
+const (
+	UniverseInvalid Universe = iota
+	UniversePublic
+	UniverseBeta
+	UniverseInternal
+	UniverseDev
+)
+
+// Type AccountType says what sort of account a SteamID identifies.
+type AccountType uint8
+
+const (
+	AccountInvalid AccountType = iota
+	AccountIndividual
+	AccountMultiseat
+	AccountGameServer
+	AccountAnonGameServer
+	AccountPending
+	AccountContentServer
+	AccountClan
+	AccountChat
+	AccountConsoleUser
+	AccountAnonUser
+)
+
+// accountTypeLetters holds the letters used for each account type in the Steam3
+// form, indexed by AccountType.
+const accountTypeLetters = "IUMGAPCgT?a"
+
+const (
+	// The usual instance for individual accounts
+	DesktopInstance = 1
+	// Instance flags for chat IDs
+	chatInstanceClan  = 0x80000
+	chatInstanceLobby = 0x40000
+	// Decimal numbers below this are taken to be 32-bit account IDs
+	minSteamID64 = 1 << 32
+)
+
+// Function NewSteamID assembles a SteamID from its parts. Only the low 20 bits
+// of instance are used.
+func NewSteamID(u Universe, t AccountType, instance, accountID uint32) SteamID {
+	return SteamID(uint64(u)<<56 | uint64(t&0xF)<<52 |
+		uint64(instance&0xFFFFF)<<32 | uint64(accountID))
+}
+
+// Function IndividualSteamID returns the SteamID of the normal public account
+// with the given 32-bit account ID.
+func IndividualSteamID(accountID uint32) SteamID {
+	return NewSteamID(UniversePublic, AccountIndividual, DesktopInstance, accountID)
+}
+
+// Method Universe returns the universe part of id.
+func (id SteamID) Universe() Universe { return Universe(id >> 56) }
+
+// Method AccountType returns the account type part of id.
+func (id SteamID) AccountType() AccountType { return AccountType(id >> 52 & 0xF) }
+
+// Method Instance returns the instance part of id.
+func (id SteamID) Instance() uint32 { return uint32(id >> 32 & 0xFFFFF) }
+
+// Method AccountID returns the 32-bit account ID part of id.
+func (id SteamID) AccountID() uint32 { return uint32(id) }
+
+// Method IsValid reports whether id plausibly identifies a Steam account.
+func (id SteamID) IsValid() bool {
+	return id.Universe() != UniverseInvalid &&
+		id.Universe() <= UniverseDev &&
+		id.AccountType() != AccountInvalid &&
+		int(id.AccountType()) < len(accountTypeLetters) &&
+		(id.AccountType() != AccountIndividual || id.AccountID() != 0)
+}
+
+/*================================ Formatting ================================*/
+
+// Method String returns id as a decimal number, which is how Steam's Web API
+// expects SteamIDs.
+func (id SteamID) String() string {
+	return strconv.FormatUint(uint64(id), 10)
+}
+
+// Method Steam2 returns id in the form "STEAM_X:Y:Z". Following newer games,
+// X is the universe number (1 for the public universe).
+func (id SteamID) Steam2() string {
+	acct := id.AccountID()
+	return fmt.Sprintf("STEAM_%d:%d:%d", id.Universe(), acct&1, acct>>1)
+}
+
+// Method Steam3 returns id in the form "[U:1:22202]". The instance is included
+// only where it is not the usual one for the account type.
+func (id SteamID) Steam3() string {
+	t, instance := id.AccountType(), id.Instance()
+	letter := byte('?')
+	if int(t) < len(accountTypeLetters) {
+		letter = accountTypeLetters[t]
+	}
+	showInstance := false
+	switch t {
+	case AccountChat:
+		if instance&chatInstanceClan != 0 {
+			letter = 'c'
+		} else if instance&chatInstanceLobby != 0 {
+			letter = 'L'
+		}
+	case AccountIndividual:
+		showInstance = instance != DesktopInstance
+	case AccountAnonGameServer, AccountMultiseat:
+		showInstance = true
+	}
+	if showInstance {
+		return fmt.Sprintf("[%c:%d:%d:%d]",
+			letter, id.Universe(), id.AccountID(), instance)
+	}
+	return fmt.Sprintf("[%c:%d:%d]", letter, id.Universe(), id.AccountID())
+}
+
+// Method ProfileURL returns the URL of the Steam Community profile page for id.
+func (id SteamID) ProfileURL() string {
+	return "https://steamcommunity.com/profiles/" + id.String()
+}
+
+/*================================= Parsing ==================================*/
+
+var (
+	regexpSteam2 = regexp.MustCompile(`^STEAM_([0-5]):([01]):(\d+)$`)
+	regexpSteam3 = regexp.MustCompile(
+		`^(\[?)([IUMGAPCgTLca]):([0-5]):(\d+)(?::(\d+))?(\]?)$`)
+	regexpProfileURL = regexp.MustCompile(
+		`^(?:https?://)?(?:www\.)?steamcommunity\.com/profiles/(\d+)/?(?:[?#].*)?$`)
+)
+
+// Function ParseSteamID parses a SteamID in any of the usual forms: a 64-bit
+// decimal number, "STEAM_X:Y:Z", "[U:1:N]" (with or without the brackets, and
+// with an optional instance), a 32-bit account ID (taken to be an individual
+// account in the public universe) or a steamcommunity.com/profiles/... URL.
+// It returns a *SteamIDError if text is none of these, or gives an invalid
+// universe or account type (see SteamID.IsValid).
+//
+// Note that ParseSteamID does not handle vanity URLs (".../id/name"), which
+// need a call to Steam; see ResolveVanityURL.
+//
+func ParseSteamID(text string) (SteamID, error) {
+	trimmed := strings.TrimSpace(text)
+	bad := func(problem string) (SteamID, error) {
+		return NullSteamID64, &SteamIDError{Text: text, Problem: problem}
+	}
+
+	if m := regexpProfileURL.FindStringSubmatch(trimmed); m != nil {
+		trimmed = m[1]
+	}
+
+	if m := regexpSteam2.FindStringSubmatch(trimmed); m != nil {
+		u, _ := strconv.Atoi(m[1])
+		if u == 0 {
+			u = int(UniversePublic) // Older games write STEAM_0:...
+		}
+		z, err := strconv.ParseUint(m[3], 10, 31)
+		if err != nil {
+			return bad("has account number out of range")
+		}
+		acct := uint32(z)<<1 | uint32(m[2][0]-'0')
+		id := NewSteamID(Universe(u), AccountIndividual, DesktopInstance, acct)
+		if !id.IsValid() {
+			return bad("is not a valid Steam2 ID")
+		}
+		return id, nil
+	}
+
+	if m := regexpSteam3.FindStringSubmatch(trimmed); m != nil {
+		if (m[1] == "") != (m[6] == "") {
+			return bad("has unbalanced brackets")
+		}
+		u, _ := strconv.Atoi(m[3])
+		acct, err := strconv.ParseUint(m[4], 10, 32)
+		if err != nil {
+			return bad("has account ID out of range")
+		}
+		t, instance := steam3Type(m[2][0])
+		if m[5] != "" {
+			i, err := strconv.ParseUint(m[5], 10, 20)
+			if err != nil {
+				return bad("has instance out of range")
+			}
+			instance = uint32(i)
+		}
+		id := NewSteamID(Universe(u), t, instance, uint32(acct))
+		if !id.IsValid() {
+			return bad("is not a valid Steam3 ID")
+		}
+		return id, nil
+	}
+
+	n, err := strconv.ParseUint(trimmed, 10, 64)
+	if err != nil {
+		return bad("is not a recognised form of SteamID")
+	} else if n == 0 {
+		return bad("is zero")
+	} else if n < minSteamID64 {
+		return IndividualSteamID(uint32(n)), nil
+	}
+	id := SteamID(n)
+	if !id.IsValid() {
+		return bad("is not a valid 64-bit SteamID")
+	}
+	return id, nil
+}
+
+// steam3Type returns the account type and default instance for a letter used
+// in the Steam3 form.
+func steam3Type(letter byte) (AccountType, uint32) {
+	switch letter {
+	case 'U':
+		return AccountIndividual, DesktopInstance
+	case 'c':
+		return AccountChat, chatInstanceClan
+	case 'L':
+		return AccountChat, chatInstanceLobby
+	case 'G':
+		return AccountGameServer, 1
+	}
+	return AccountType(strings.IndexByte(accountTypeLetters, letter)), 0
+}
+
+/*============================= Encoding SteamIDs ============================*/
+
+// Method MarshalText encodes id as a decimal number. (This also lets SteamIDs
+// be keys of maps encoded as JSON.)
+func (id SteamID) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+// Method UnmarshalText accepts any form that ParseSteamID does.
+func (id *SteamID) UnmarshalText(text []byte) error {
+	parsed, err := ParseSteamID(string(text))
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
+
+// Method MarshalJSON encodes id as a JSON string, as Steam does, since many
+// JSON decoders cannot handle 64-bit integers exactly.
+func (id SteamID) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + id.String() + `"`), nil
+}
+
+// Method UnmarshalJSON accepts a SteamID as either a JSON string (in any form
+// that ParseSteamID does) or a JSON number. It leaves id unchanged for null.
+func (id *SteamID) UnmarshalJSON(data []byte) error {
+	text := string(data)
+	if text == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(text); err == nil {
+		text = unquoted
+	}
+	return id.UnmarshalText([]byte(text))
+}
+
+/*================================== Errors ==================================*/
+
+// Type SteamIDError represents text which cannot be parsed as a SteamID.
+type SteamIDError struct {
+	Text    string // The text in question
+	Problem string // What is wrong with it
+}
+
+func (e *SteamIDError) Error() string {
+	return fmt.Sprintf("cannot parse %q as a SteamID: it %s", e.Text, e.Problem)
+}
@@ -0,0 +1,107 @@
+package SteamAPI
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// gabeN is the SteamID used in Valve's own examples.
+var gabeN = IndividualSteamID(22202)
+
+func TestParseSteamID(t *testing.T) {
+	for _, text := range []string{
+		"76561197960287930",
+		" 76561197960287930\n",
+		"STEAM_0:0:11101",
+		"STEAM_1:0:11101",
+		"[U:1:22202]",
+		"U:1:22202",
+		"[U:1:22202:1]",
+		"22202",
+		"https://steamcommunity.com/profiles/76561197960287930",
+		"steamcommunity.com/profiles/76561197960287930/?l=english",
+	} {
+		id, err := ParseSteamID(text)
+		if err != nil {
+			t.Errorf("ParseSteamID(%q): %v", text, err)
+		} else if id != gabeN {
+			t.Errorf("ParseSteamID(%q) = %d, want %d", text, id, gabeN)
+		}
+	}
+}
+
+func TestParseSteamIDErrors(t *testing.T) {
+	badType := NewSteamID(UniversePublic, 15, DesktopInstance, 22202)
+	for _, text := range []string{
+		"",
+		"0",
+		"gabe",
+		"[U:1:22202",
+		"U:1:22202]",
+		"[U:9:22202]",
+		"[U:1:99999999999]",
+		"[U:1:22202:9999999]",
+		"STEAM_0:0:9999999999",
+		"https://steamcommunity.com/id/gabelogannewell",
+		badType.String(),
+	} {
+		id, err := ParseSteamID(text)
+		var idErr *SteamIDError
+		if !errors.As(err, &idErr) {
+			t.Errorf("ParseSteamID(%q) = %d, %v; want a *SteamIDError", text, id, err)
+		} else if idErr.Text != text {
+			t.Errorf("ParseSteamID(%q): error has Text %q", text, idErr.Text)
+		}
+	}
+	for _, text := range []string{"[U:1:22202", "U:1:22202]"} {
+		_, err := ParseSteamID(text)
+		var idErr *SteamIDError
+		if errors.As(err, &idErr) && idErr.Problem != "has unbalanced brackets" {
+			t.Errorf("ParseSteamID(%q): got problem %q, want unbalanced brackets",
+				text, idErr.Problem)
+		}
+	}
+}
+
+func TestSteamIDForms(t *testing.T) {
+	if got := gabeN.Steam2(); got != "STEAM_1:0:11101" {
+		t.Errorf("Steam2() = %q, want %q", got, "STEAM_1:0:11101")
+	}
+	if got := gabeN.Steam3(); got != "[U:1:22202]" {
+		t.Errorf("Steam3() = %q, want %q", got, "[U:1:22202]")
+	}
+	clanChat := NewSteamID(UniversePublic, AccountChat, chatInstanceClan, 5)
+	for _, id := range []SteamID{gabeN, clanChat,
+		NewSteamID(UniversePublic, AccountIndividual, 2, 22202)} {
+		for _, text := range []string{id.String(), id.Steam3(), id.ProfileURL()} {
+			if got, err := ParseSteamID(text); err != nil || got != id {
+				t.Errorf("ParseSteamID(%q) = %d, %v; want %d", text, got, err, id)
+			}
+		}
+	}
+}
+
+func TestSteamIDJSON(t *testing.T) {
+	var v struct{ A, B, C SteamID }
+	v.C = gabeN
+	err := json.Unmarshal(
+		[]byte(`{"A":"76561197960287930","B":76561197960287930,"C":null}`), &v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.A != gabeN || v.B != gabeN || v.C != gabeN {
+		t.Errorf("decoded %+v, want %d for all three", v, gabeN)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"A":"76561197960287930","B":"76561197960287930","C":"76561197960287930"}`
+	if string(data) != want {
+		t.Errorf("json.Marshal = %s, want %s", data, want)
+	}
+	if err := json.Unmarshal([]byte(`{"A":"[U:1:22202"}`), &v); err == nil {
+		t.Error("decoding an unbalanced Steam3 ID gave no error")
+	}
+}
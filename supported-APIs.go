@@ -0,0 +1,166 @@
+package SteamAPI
+
+// This file provides the list of interfaces, methods and parameters returned by
+// ISteamWebAPIUtil/GetSupportedAPIList. Which methods that list includes
+// depends on the key used (if any), so we cache one copy per key.
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"time"
+)
+
+type (
+	// Type SupportedAPIList describes the Web API, as returned by
+	// ISteamWebAPIUtil/GetSupportedAPIList.
+	SupportedAPIList struct {
+		Interfaces []APIInterface `json:"interfaces"`
+	}
+
+	// Type APIInterface describes one interface, such as "ISteamUser".
+	APIInterface struct {
+		Name    string      `json:"name"`
+		Methods []APIMethod `json:"methods"`
+	}
+
+	// Type APIMethod describes one version of one method. (Steam lists each
+	// version of a method separately.)
+	APIMethod struct {
+		Name        string         `json:"name"`
+		Version     int            `json:"version"`
+		HTTPMethod  string         `json:"httpmethod"` // "GET" or "POST"
+		Description string         `json:"description,omitempty"`
+		Parameters  []APIParameter `json:"parameters"`
+	}
+
+	// Type APIParameter describes one parameter of a method. Array parameters
+	// have names like "steamids[0]".
+	APIParameter struct {
+		Name        string `json:"name"`
+		Type        string `json:"type"` // "string", "uint32", "bool", etc
+		Optional    bool   `json:"optional"`
+		Description string `json:"description,omitempty"`
+	}
+
+	// supportedAPIListResponse is the JSON form returned by Steam, which we
+	// also use for our cache files.
+	supportedAPIListResponse struct {
+		APIList SupportedAPIList `json:"apilist"`
+	}
+)
+
+/*=============================== Getting Lists ==============================*/
+
+// Function GetSupportedAPIList calls DefaultClient.GetSupportedAPIList.
+func GetSupportedAPIList(ctx context.Context, withKey bool, maxAge time.Duration,
+) (*SupportedAPIList, error) {
+	return DefaultClient.GetSupportedAPIList(ctx, withKey, maxAge)
+}
+
+// Method GetSupportedAPIList returns the list of supported APIs from the cache,
+// if the cached copy is less than maxAge old, or else from Steam (in which case
+// it updates the cache).
+//
+// If withKey is true, the request includes c's key, and so the list includes
+// any methods only available to that key.
+//
+func (c *Client) GetSupportedAPIList(ctx context.Context, withKey bool,
+	maxAge time.Duration,
+) (*SupportedAPIList, error) {
+	flags, key := UseHTTPS, ""
+	if withKey {
+		var err error
+		flags = UseKey
+		key, err = c.apiKey()
+		if err != nil {
+			return nil, err
+		}
+	}
+	path, err := c.apiListPath(key)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp supportedAPIListResponse
+	found, err := ReadCachedJSON(path, maxAge, &resp)
+	if found {
+		return &resp.APIList, nil
+	} else if err != nil {
+		c.logf("ignoring cached API list: %s", err)
+	}
+
+	err = c.GetJSONValues(ctx, &resp, "list of supported APIs", "",
+		"ISteamWebAPIUtil", "GetSupportedAPIList", 1, flags, nil)
+	if err != nil {
+		return nil, err
+	}
+	err = WriteCachedJSON(path, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp.APIList, nil
+}
+
+func (c *Client) apiListPath(key string) (string, error) {
+	dir, err := c.cacheDir()
+	return filepath.Join(dir, "SupportedAPIList", quotaKeyID(key)+".json"), err
+}
+
+// Function LoadSupportedAPIList reads a list of supported APIs from a file
+// holding JSON as returned by GetSupportedAPIList, such as a saved copy of
+//	https://api.steampowered.com/ISteamWebAPIUtil/GetSupportedAPIList/v1/
+//
+func LoadSupportedAPIList(path string) (*SupportedAPIList, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, &CacheError{Action: "read", Path: path, BaseError: err}
+	}
+	var resp supportedAPIListResponse
+	err = json.Unmarshal(contents, &resp)
+	if err != nil {
+		return nil, &CacheError{Action: "parse", Path: path, BaseError: err}
+	}
+	return &resp.APIList, nil
+}
+
+/*============================== Searching Lists =============================*/
+
+// Method Interface returns the named interface, or nil if l does not list it.
+func (l *SupportedAPIList) Interface(name string) *APIInterface {
+	for i := range l.Interfaces {
+		if l.Interfaces[i].Name == name {
+			return &l.Interfaces[i]
+		}
+	}
+	return nil
+}
+
+// Method Method returns the given version of the named method of the named
+// interface, or nil if l does not list it.
+func (l *SupportedAPIList) Method(iface, method string, version int) *APIMethod {
+	if ai := l.Interface(iface); ai != nil {
+		for i := range ai.Methods {
+			m := &ai.Methods[i]
+			if m.Name == method && m.Version == version {
+				return m
+			}
+		}
+	}
+	return nil
+}
+
+// Method Versions returns the versions of the named method of the named
+// interface which l lists, in the order l lists them.
+func (l *SupportedAPIList) Versions(iface, method string) []int {
+	var versions []int
+	if ai := l.Interface(iface); ai != nil {
+		for _, m := range ai.Methods {
+			if m.Name == method {
+				versions = append(versions, m.Version)
+			}
+		}
+	}
+	return versions
+}
@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
 )
 
 // Type SteamItemID holds an 'app id' (a positive integer) denoting a Steam App
@@ -19,6 +22,104 @@ type SteamItemID uint32
 // NullSteamID is the zero value for a SteamItemID.
 const NullSteamID = SteamItemID(0)
 
+// Method String returns id as a decimal number.
+func (id SteamItemID) String() string {
+	return strconv.FormatUint(uint64(id), 10)
+}
+
+/*=============================== Store Items ================================*/
+
+// Type StoreItemKind says what sort of thing a StoreItem denotes.
+type StoreItemKind uint8
+
+const (
+	StoreApp    StoreItemKind = iota + 1 // An app: a game, DLC, soundtrack, ...
+	StoreSub                             // A sub (AKA package): what is sold
+	StoreBundle                          // A bundle of subs, sold at a discount
+)
+
+var storeItemKindNames = [...]string{"", "app", "sub", "bundle"}
+
+// Method String returns "app", "sub" or "bundle", as in store URLs.
+func (k StoreItemKind) String() string {
+	if int(k) < len(storeItemKindNames) && k != 0 {
+		return storeItemKindNames[k]
+	}
+	return fmt.Sprintf("StoreItemKind(%d)", k)
+}
+
+// Type StoreItem identifies something the Steam store has a page for.
+type StoreItem struct {
+	Kind StoreItemKind
+	ID   SteamItemID
+}
+
+// Functions AppItem, SubItem and BundleItem return StoreItems of each kind.
+func AppItem(id SteamItemID) StoreItem    { return StoreItem{StoreApp, id} }
+func SubItem(id SteamItemID) StoreItem    { return StoreItem{StoreSub, id} }
+func BundleItem(id SteamItemID) StoreItem { return StoreItem{StoreBundle, id} }
+
+// Method String returns item in the form used in store URLs, like "app/440".
+func (item StoreItem) String() string {
+	return item.Kind.String() + "/" + item.ID.String()
+}
+
+// Method URL returns the URL of item's store page.
+func (item StoreItem) URL() string {
+	return "https://store.steampowered.com/" + item.String() + "/"
+}
+
+var regexpStoreItem = regexp.MustCompile(
+	`^(?:(?:https?://)?store\.steampowered\.com/|/)?(app|sub|bundle)/(\d+)(?:[/?#].*)?$`)
+
+// Function ParseStoreItem parses a store URL like
+// "https://store.steampowered.com/sub/469/" (with or without the scheme and
+// host, and with or without anything after the number), or a bare number,
+// which it takes to be an app ID. It returns a *StoreItemError if s is not in
+// one of those forms.
+//
+func ParseStoreItem(s string) (StoreItem, error) {
+	trimmed := strings.TrimSpace(s)
+	kind, digits := "app", trimmed
+	if m := regexpStoreItem.FindStringSubmatch(trimmed); m != nil {
+		kind, digits = m[1], m[2]
+	}
+	n, err := strconv.ParseUint(digits, 10, 32)
+	if err != nil || n == 0 {
+		return StoreItem{}, &StoreItemError{Input: s}
+	}
+	for k, name := range storeItemKindNames {
+		if name == kind {
+			return StoreItem{StoreItemKind(k), SteamItemID(n)}, nil
+		}
+	}
+	return StoreItem{}, &StoreItemError{Input: s}
+}
+
+// Method MarshalText returns item in the form used by String.
+func (item StoreItem) MarshalText() ([]byte, error) {
+	return []byte(item.String()), nil
+}
+
+// Method UnmarshalText accepts any form that ParseStoreItem does.
+func (item *StoreItem) UnmarshalText(text []byte) error {
+	parsed, err := ParseStoreItem(string(text))
+	if err != nil {
+		return err
+	}
+	*item = parsed
+	return nil
+}
+
+// Type StoreItemError is returned by ParseStoreItem for bad input.
+type StoreItemError struct {
+	Input string
+}
+
+func (e *StoreItemError) Error() string {
+	return fmt.Sprintf("cannot parse %q as a Steam store item", e.Input)
+}
+
 /*=============================== Directories ================================*/
 
 // FIXME: should use basedirs here, once I write it.
@@ -42,15 +143,29 @@ func ConfigDirPath() string {
 }
 
 func CacheDirPath() string {
+	dir, err := cacheDirPath()
+	if err != nil {
+		panic(err.Error())
+	}
+	return dir
+}
+
+// cacheDirPath is like CacheDirPath, but returns a *CacheError instead of
+// panicking if there is no usable cache directory.
+func cacheDirPath() (string, error) {
 	if moduleCacheDir == "" {
 		dir, err := os.UserCacheDir()
 		if err != nil {
-			panic("os.UserCacheDir() failed: " + err.Error())
+			return "", &CacheError{Action: "find the cache directory for",
+				Path: baseDirsRelPath, BaseError: err}
+		}
+		dir = filepath.Join(dir, baseDirsRelPath)
+		if err := makeCacheDir(dir); err != nil {
+			return "", err
 		}
-		moduleCacheDir = filepath.Join(dir, baseDirsRelPath)
-		EnsureDirExists(moduleCacheDir)
+		moduleCacheDir = dir
 	}
-	return moduleCacheDir
+	return moduleCacheDir, nil
 }
 
 func EnsureDirExists(path string) {
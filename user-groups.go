@@ -0,0 +1,112 @@
+package SteamAPI
+
+// This file provides ISteamUser/GetUserGroupList, which lists the Steam groups
+// a user belongs to.
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+)
+
+// Type UserGroupsResult holds the result of GetUserGroupLists.
+type UserGroupsResult struct {
+	Groups  map[SteamID][]SteamID // The SteamIDs of each user's groups
+	Missing []SteamID             // Users Steam would not list groups for
+}
+
+// Function GetUserGroupList calls DefaultClient.GetUserGroupList.
+func GetUserGroupList(ctx context.Context, id SteamID) ([]SteamID, error) {
+	return DefaultClient.GetUserGroupList(ctx, id)
+}
+
+// Method GetUserGroupList returns the SteamIDs of the groups user 'id' belongs
+// to, using ISteamUser/GetUserGroupList/v1.
+//
+// Steam answers 403 Forbidden, or reports failure, if the user's profile is
+// private; GetUserGroupList then returns an error wrapping ErrPrivateProfile.
+//
+func (c *Client) GetUserGroupList(ctx context.Context, id SteamID,
+) ([]SteamID, error) {
+	who := "user " + id.String()
+	var resp struct {
+		Response struct {
+			Success bool   `json:"success"`
+			Error   string `json:"error"`
+			Groups  []struct {
+				GID string `json:"gid"`
+			} `json:"groups"`
+		} `json:"response"`
+	}
+	err := c.GetJSONValues(ctx, &resp, "group list", who,
+		"ISteamUser", "GetUserGroupList", 1, UseKey,
+		NewParams().SteamID("steamid", id).Values())
+	if err != nil {
+		return nil, markPrivateProfile(err)
+	} else if !resp.Response.Success {
+		return nil, &WebError{Action: "get", What: "group list", Who: who,
+			BaseError: ErrPrivateProfile}
+	}
+
+	groups := make([]SteamID, 0, len(resp.Response.Groups))
+	for _, g := range resp.Response.Groups {
+		n, err := strconv.ParseUint(g.GID, 10, 64)
+		if err != nil {
+			return nil, &WebError{Action: "parse", What: "group list", Who: who,
+				BaseError: err}
+		}
+		// Steam gives the 32-bit account IDs of the groups
+		if n < minSteamID64 {
+			groups = append(groups,
+				NewSteamID(UniversePublic, AccountClan, 0, uint32(n)))
+		} else {
+			groups = append(groups, SteamID(n))
+		}
+	}
+	return groups, nil
+}
+
+// Function GetUserGroupLists calls DefaultClient.GetUserGroupLists.
+func GetUserGroupLists(ctx context.Context, ids ...SteamID,
+) (*UserGroupsResult, error) {
+	return DefaultClient.GetUserGroupLists(ctx, ids...)
+}
+
+// Method GetUserGroupLists returns the groups of each of the given users,
+// ignoring any duplicate SteamIDs. GetUserGroupList takes one SteamID per call,
+// so this sends one request per user, up to c.MaxConcurrency at once.
+//
+// Users whose profiles are private are listed in the result's Missing field.
+// If any other request fails, GetUserGroupLists returns that error (and no
+// results).
+//
+func (c *Client) GetUserGroupLists(ctx context.Context, ids ...SteamID,
+) (*UserGroupsResult, error) {
+	ids = uniqueSteamIDs(ids)
+	var mu sync.Mutex
+	result := &UserGroupsResult{Groups: make(map[SteamID][]SteamID, len(ids))}
+	err := c.forEachBatch(ctx, len(ids), 1,
+		func(ctx context.Context, lo, hi int) error {
+			groups, err := c.GetUserGroupList(ctx, ids[lo])
+			if errors.Is(err, ErrPrivateProfile) {
+				return nil
+			} else if err != nil {
+				return err
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			result.Groups[ids[lo]] = groups
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		if _, ok := result.Groups[id]; !ok {
+			result.Missing = append(result.Missing, id)
+		}
+	}
+	return result, nil
+}
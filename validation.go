@@ -0,0 +1,208 @@
+package SteamAPI
+
+// This file checks calls against a list of supported APIs before they are
+// sent, so that mistakes like a misspelt method name or a missing parameter
+// are reported locally instead of costing a call (and a 404 or 400) each.
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// APIListMaxAge is how old a cached list of supported APIs can be before a
+// Client validating calls fetches a new one.
+const APIListMaxAge = 24 * time.Hour
+
+// Method ValidateCall checks a call to version 'version' of iface/method with
+// the given flags and parameters against l. It returns nil if the call looks
+// valid, or else a *ValidationError describing the first problem found.
+//
+func (l *SupportedAPIList) ValidateCall(
+	iface, method string,
+	version int,
+	flags int,
+	params url.Values,
+) error {
+	problem := func(format string, args ...interface{}) error {
+		return &ValidationError{Iface: iface, Method: method, Version: version,
+			Problem: fmt.Sprintf(format, args...)}
+	}
+	if l.Interface(iface) == nil {
+		return problem("no such interface")
+	}
+	m := l.Method(iface, method, version)
+	if m == nil {
+		versions := l.Versions(iface, method)
+		if len(versions) == 0 {
+			return problem("no such method")
+		}
+		return problem("no such version (try %s)", versionList(versions))
+	}
+	if m.HTTPMethod != "" && m.HTTPMethod != "GET" {
+		return problem("method needs HTTP %s, not GET", m.HTTPMethod)
+	}
+
+	declared := make(map[string]bool, len(m.Parameters))
+	for _, p := range m.Parameters {
+		declared[arrayBaseName(p.Name)] = true
+	}
+	var unknown []string
+	for name := range params {
+		if !declared[arrayBaseName(name)] && !genericParams[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return problem("unknown parameter(s) %s", quotedList(unknown))
+	}
+
+	var missing []string
+	for _, p := range m.Parameters {
+		if p.Optional {
+			continue
+		} else if p.Name == "key" {
+			if flags&useKey == 0 {
+				return problem("method needs a key")
+			}
+		} else if !hasParam(params, p.Name) {
+			missing = append(missing, p.Name)
+		}
+	}
+	if len(missing) > 0 {
+		return problem("missing parameter(s) %s", quotedList(missing))
+	}
+	return nil
+}
+
+// genericParams lists parameters which every method accepts, but which the
+// list of supported APIs does not mention.
+var genericParams = map[string]bool{"key": true, "format": true, "input_json": true}
+
+var regexpArrayIndex = regexp.MustCompile(`\[\d+\]$`)
+
+// arrayBaseName turns names like "steamids[3]" into "steamids[]".
+func arrayBaseName(name string) string {
+	return regexpArrayIndex.ReplaceAllString(name, "[]")
+}
+
+// hasParam reports whether params includes name or, if name is an array
+// element, any element of the same array.
+func hasParam(params url.Values, name string) bool {
+	if _, present := params[name]; present {
+		return true
+	}
+	base := arrayBaseName(name)
+	if base == name {
+		return false
+	}
+	for n := range params {
+		if arrayBaseName(n) == base {
+			return true
+		}
+	}
+	return false
+}
+
+func versionList(versions []int) string {
+	texts := make([]string, len(versions))
+	for i, v := range versions {
+		texts[i] = fmt.Sprintf("v%d", v)
+	}
+	return strings.Join(texts, ", ")
+}
+
+func quotedList(names []string) string {
+	texts := make([]string, len(names))
+	for i, n := range names {
+		texts[i] = fmt.Sprintf("%q", n)
+	}
+	return strings.Join(texts, ", ")
+}
+
+/*============================ Validating Clients ============================*/
+
+// validateCall checks a call against c.APIList or, if that is nil, against the
+// (cached) list for c's key or for no key.
+func (c *Client) validateCall(ctx context.Context,
+	iface, method string,
+	version int,
+	flags int,
+	params url.Values,
+) error {
+	if iface == "ISteamWebAPIUtil" && method == "GetSupportedAPIList" {
+		return nil // Avoid needing the list to get the list
+	}
+	list := c.APIList
+	if list == nil {
+		var err error
+		list, err = c.cachedAPIList(ctx, flags&useKey != 0)
+		if err != nil {
+			return err
+		}
+	}
+	return list.ValidateCall(iface, method, version, flags, params)
+}
+
+// loadedAPILists holds lists of supported APIs read by cachedAPIList, so that
+// validating a call does not mean parsing a large file.
+var loadedAPILists = struct {
+	sync.Mutex
+	byPath map[string]loadedAPIList
+}{byPath: make(map[string]loadedAPIList)}
+
+type loadedAPIList struct {
+	list     *SupportedAPIList
+	loadedAt time.Time
+}
+
+func (c *Client) cachedAPIList(ctx context.Context, withKey bool,
+) (*SupportedAPIList, error) {
+	key := ""
+	if withKey {
+		var err error
+		key, err = c.apiKey()
+		if err != nil {
+			return nil, err
+		}
+	}
+	path, err := c.apiListPath(key)
+	if err != nil {
+		return nil, err
+	}
+
+	loadedAPILists.Lock()
+	defer loadedAPILists.Unlock()
+	loaded, found := loadedAPILists.byPath[path]
+	if found && time.Since(loaded.loadedAt) < APIListMaxAge {
+		return loaded.list, nil
+	}
+	list, err := c.GetSupportedAPIList(ctx, withKey, APIListMaxAge)
+	if err != nil {
+		return nil, err
+	}
+	loadedAPILists.byPath[path] = loadedAPIList{list, time.Now()}
+	return list, nil
+}
+
+/*================================== Errors ==================================*/
+
+// Type ValidationError represents a call which does not match the list of
+// supported APIs.
+type ValidationError struct {
+	Iface   string // The interface named in the call
+	Method  string // The method named in the call
+	Version int    // The version named in the call
+	Problem string // What is wrong
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("bad Steam Web API call %s/%s/v%d: %s",
+		e.Iface, e.Method, e.Version, e.Problem)
+}
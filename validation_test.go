@@ -0,0 +1,154 @@
+package SteamAPI
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+// testAPIList is a small list of supported APIs, in the form Steam returns.
+const testAPIList = `{"apilist":{"interfaces":[
+{"name":"ISteamUser","methods":[
+ {"name":"GetFriendList","version":1,"httpmethod":"GET","parameters":[
+  {"name":"key","type":"string","optional":false},
+  {"name":"steamid","type":"uint64","optional":false},
+  {"name":"relationship","type":"string","optional":true}]},
+ {"name":"GetPlayerSummaries","version":2,"httpmethod":"GET","parameters":[
+  {"name":"key","type":"string","optional":false},
+  {"name":"steamids","type":"string","optional":false}]},
+ {"name":"GetPlayerSummaries","version":1,"httpmethod":"GET","parameters":[
+  {"name":"key","type":"string","optional":false},
+  {"name":"steamids","type":"string","optional":false}]},
+ {"name":"SetSomething","version":1,"httpmethod":"POST","parameters":[]}]},
+{"name":"ISteamNews","methods":[
+ {"name":"GetNewsForApps","version":1,"httpmethod":"GET","parameters":[
+  {"name":"appids[0]","type":"uint32","optional":false},
+  {"name":"count","type":"uint32","optional":true}]}]}]}}`
+
+func loadTestAPIList(t *testing.T) *SupportedAPIList {
+	t.Helper()
+	var resp supportedAPIListResponse
+	if err := json.Unmarshal([]byte(testAPIList), &resp); err != nil {
+		t.Fatal(err)
+	}
+	return &resp.APIList
+}
+
+func TestValidateCall(t *testing.T) {
+	l := loadTestAPIList(t)
+	for _, tc := range []struct {
+		iface, method string
+		version       int
+		flags         int
+		params        []string
+		problem       string // "" if valid
+	}{
+		{"ISteamUser", "GetFriendList", 1, UseKey, []string{"steamid", "1"}, ""},
+		{"ISteamUser", "GetFriendList", 1, UseKey,
+			[]string{"steamid", "1", "relationship", "all", "format", "json"}, ""},
+		{"ISteamNews", "GetNewsForApps", 1, 0,
+			[]string{"appids[0]", "440", "appids[1]", "10"}, ""},
+		{"ISteamNews", "GetNewsForApps", 1, 0, []string{"appids[3]", "440"}, ""},
+
+		{"ISteamUsers", "GetFriendList", 1, UseKey, []string{"steamid", "1"},
+			"no such interface"},
+		{"ISteamUser", "GetFriendsList", 1, UseKey, []string{"steamid", "1"},
+			"no such method"},
+		{"ISteamUser", "GetPlayerSummaries", 3, UseKey, []string{"steamids", "1"},
+			"no such version (try v2, v1)"},
+		{"ISteamUser", "SetSomething", 1, UseKey, nil,
+			"method needs HTTP POST, not GET"},
+		{"ISteamUser", "GetFriendList", 1, UseHTTPS, []string{"steamid", "1"},
+			"method needs a key"},
+		{"ISteamUser", "GetFriendList", 1, UseKey, nil,
+			`missing parameter(s) "steamid"`},
+		{"ISteamUser", "GetFriendList", 1, UseKey,
+			[]string{"steamid", "1", "steamids", "2", "count", "3"},
+			`unknown parameter(s) "count", "steamids"`},
+		{"ISteamNews", "GetNewsForApps", 1, 0, []string{"count", "3"},
+			`missing parameter(s) "appids[0]"`},
+	} {
+		values, err := pairsToValues(tc.params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = l.ValidateCall(tc.iface, tc.method, tc.version, tc.flags, values)
+		call := tc.iface + "/" + tc.method
+		var valErr *ValidationError
+		if tc.problem == "" {
+			if err != nil {
+				t.Errorf("%s with %q: %v", call, tc.params, err)
+			}
+		} else if !errors.As(err, &valErr) {
+			t.Errorf("%s with %q: got %v, want a *ValidationError", call,
+				tc.params, err)
+		} else if valErr.Problem != tc.problem {
+			t.Errorf("%s with %q: Problem = %q, want %q", call, tc.params,
+				valErr.Problem, tc.problem)
+		}
+	}
+}
+
+// apiListServer is a stand-in for the Web API which serves testAPIList and
+// answers any other request with an empty JSON object.
+type apiListServer struct {
+	listCalls, otherCalls int
+}
+
+func (as *apiListServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/ISteamWebAPIUtil/GetSupportedAPIList/v1/" {
+		as.listCalls++
+		w.Write([]byte(testAPIList))
+		return
+	}
+	as.otherCalls++
+	w.Write([]byte("{}"))
+}
+
+func TestClientValidateCalls(t *testing.T) {
+	dir, err := ioutil.TempDir("", "validate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	as := &apiListServer{}
+	server := httptest.NewServer(as)
+	defer server.Close()
+	c := &Client{BaseURL: server.URL, Key: StaticKey("validate-test"),
+		CacheDir: dir, Flags: ValidateCalls}
+
+	var v struct{}
+	ctx := context.Background()
+	err = c.GetJSONContext(ctx, &v, "friends", "", "ISteamUser", "GetFriendList",
+		1, UseKey, "steamid", "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = c.GetJSONContext(ctx, &v, "friends", "", "ISteamUser", "GetFriendList",
+		1, UseKey)
+	var valErr *ValidationError
+	if !errors.As(err, &valErr) || !strings.Contains(err.Error(), "steamid") {
+		t.Errorf("call with no steamid: got %v, want a *ValidationError", err)
+	}
+	if as.listCalls != 1 || as.otherCalls != 1 {
+		t.Errorf("server got %d list calls and %d others, want 1 and 1",
+			as.listCalls, as.otherCalls)
+	}
+
+	// An explicit list means no need to fetch one.
+	as.listCalls = 0
+	c.APIList = loadTestAPIList(t)
+	c.Key = StaticKey("another key")
+	err = c.GetJSONContext(ctx, &v, "news", "", "ISteamNews", "GetNewsForApps", 1, 0,
+		"appids[0]", "440")
+	if err != nil || as.listCalls != 0 {
+		t.Errorf("with APIList set: got %v after %d list calls, want nil after 0",
+			err, as.listCalls)
+	}
+}
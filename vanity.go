@@ -0,0 +1,130 @@
+package SteamAPI
+
+// This file resolves ‘vanity URLs’ (like https://steamcommunity.com/id/name/)
+// to SteamIDs, via ISteamUser/ResolveVanityURL, caching the results since they
+// rarely change.
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// VanityCacheMaxAge is how long ResolveVanityURL trusts a cached resolution.
+// (Users can change their vanity URLs, but seldom do.)
+const VanityCacheMaxAge = 7 * 24 * time.Hour
+
+// steamSuccessNoMatch is the "success" value Steam returns when a vanity name
+// matches nobody.
+const steamSuccessNoMatch = 42
+
+var (
+	regexpVanityURL = regexp.MustCompile(
+		`^(?:https?://)?(?:www\.)?steamcommunity\.com/id/([^/?#]+)/?(?:[?#].*)?$`)
+	regexpVanityName = regexp.MustCompile(`^[A-Za-z0-9_-]{2,32}$`)
+)
+
+// Function VanityName returns the vanity name from nameOrURL, which can be a
+// bare name or a URL like "https://steamcommunity.com/id/name/". It returns a
+// *SteamIDError if nameOrURL is neither.
+//
+func VanityName(nameOrURL string) (string, error) {
+	name := strings.TrimSpace(nameOrURL)
+	if m := regexpVanityURL.FindStringSubmatch(name); m != nil {
+		name = m[1]
+	}
+	if !regexpVanityName.MatchString(name) {
+		return "", &SteamIDError{Text: nameOrURL,
+			Problem: "is not a vanity name or URL"}
+	}
+	return name, nil
+}
+
+// Function ResolveVanityURL calls DefaultClient.ResolveVanityURL.
+func ResolveVanityURL(ctx context.Context, nameOrURL string) (SteamID, error) {
+	return DefaultClient.ResolveVanityURL(ctx, nameOrURL)
+}
+
+// Method ResolveVanityURL returns the SteamID of the user with the given vanity
+// name or URL, from the cache if it has a resolution less than
+// VanityCacheMaxAge old, or else by calling ISteamUser/ResolveVanityURL (which
+// needs a key).
+//
+// As a convenience, it also accepts "steamcommunity.com/profiles/..." URLs,
+// which it parses without calling Steam.
+//
+// If Steam reports no match, ResolveVanityURL returns a *VanityNotFoundError,
+// which matches ErrNotFound for errors.Is.
+//
+func (c *Client) ResolveVanityURL(ctx context.Context, nameOrURL string,
+) (SteamID, error) {
+	trimmed := strings.TrimSpace(nameOrURL)
+	if m := regexpProfileURL.FindStringSubmatch(trimmed); m != nil {
+		return ParseSteamID(m[1])
+	}
+	name, err := VanityName(nameOrURL)
+	if err != nil {
+		return NullSteamID64, err
+	}
+
+	cacheDir, err := c.cacheDir()
+	if err != nil {
+		return NullSteamID64, err
+	}
+	// Vanity names are not case-sensitive.
+	path := filepath.Join(cacheDir, "vanity", strings.ToLower(name)+".json")
+	var cached struct{ SteamID SteamID }
+	found, err := ReadCachedJSON(path, VanityCacheMaxAge, &cached)
+	if found && cached.SteamID != NullSteamID64 {
+		return cached.SteamID, nil
+	} else if err != nil {
+		c.logf("ignoring cached vanity URL: %s", err)
+	}
+
+	var resp struct {
+		Response struct {
+			SteamID SteamID `json:"steamid"`
+			Success int     `json:"success"`
+			Message string  `json:"message"`
+		} `json:"response"`
+	}
+	err = c.GetJSONValues(ctx, &resp, "SteamID", "vanity name "+name,
+		"ISteamUser", "ResolveVanityURL", 1, UseKey,
+		NewParams().Set("vanityurl", name).Int("url_type", 1).Values())
+	if err != nil {
+		return NullSteamID64, err
+	}
+	r := &resp.Response
+	if r.Success == steamSuccessNoMatch {
+		return NullSteamID64, &VanityNotFoundError{Name: name, Message: r.Message}
+	} else if r.Success != 1 || r.SteamID == NullSteamID64 {
+		problem := fmt.Errorf("Steam returned success=%d, message %q",
+			r.Success, r.Message)
+		return NullSteamID64, &WebError{Action: "resolve",
+			What: "vanity name", Who: name, BaseError: problem}
+	}
+
+	cached.SteamID = r.SteamID
+	err = WriteCachedJSON(path, &cached)
+	if err != nil {
+		c.logf("cannot cache vanity URL: %s", err)
+	}
+	return r.SteamID, nil
+}
+
+// Type VanityNotFoundError represents a vanity name which Steam does not know.
+// It matches ErrNotFound for errors.Is.
+type VanityNotFoundError struct {
+	Name    string // The vanity name
+	Message string // Steam's explanation, usually "No match"
+}
+
+func (e *VanityNotFoundError) Error() string {
+	return fmt.Sprintf("Steam found no user with vanity name %q (%s)",
+		e.Name, e.Message)
+}
+
+func (e *VanityNotFoundError) Is(target error) bool { return target == ErrNotFound }
@@ -151,7 +151,7 @@ func (e *SteamKeyError) Unwrap() error { return e.BaseError }
 func (e *SteamKeyError) Error() string {
 	text := ""
 	if e.Path != "" {
-		text = fmt.Sprintf("file %q%s", e.Path)
+		text = fmt.Sprintf("file %q", e.Path)
 	}
 	text = fmt.Sprintf("cannot %s %s%s", e.Action, text, e.Details)
 	if e.BaseError != nil {
@@ -18,7 +18,8 @@ package SteamAPI
 //
 // Note that getting a user key requires you to agree to the Steam Web API Terms of Use
 // (https://steamcommunity.com/dev/apiterms), which includes a limit of 100,000 calls per
-// day to the Web API.
+// day to the Web API. A Client with a Quota keeps count of calls, and refuses to make
+// calls beyond a budget.
 //
 // For details, start browsing at https://partner.steamgames.com/doc/webapi_overview and
 // https://developer.valvesoftware.com/wiki/Steam_Web_API.
@@ -27,139 +28,668 @@ package SteamAPI
 //
 // Every Steam user has a permanent SteamID, which is a unique (and large) number. Users
 // also have a profile name, but that need not be unique and is easily changed. Users can
-// opt to have a "vanity URL" ...???
+// opt to have a "vanity URL" like https://steamcommunity.com/id/$NAME/, as well as the
+// permanent https://steamcommunity.com/profiles/$STEAMID; ResolveVanityURL turns vanity
+// names into SteamIDs.
 //
 // Steam also use numeric entities for various other entities. For example, each App has
 // an "AppID" (and "https://store.steampowered.com/app/$AppID" will take you to its Store
-// page). Nonetheless, "SteamID" always refers to a user's numeric ID.
+// page). Nonetheless, "SteamID" always refers to a user's numeric ID. This package uses
+// type SteamID for those, and type SteamItemID for AppIDs.
 
 // the .../steamAPI-c12h/API-access-key.txt could not be
 // used.  configuration file $USER_CONFIG/ (where $USER_CONFIG is the
 //
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
+	"net/url"
+	"regexp"
 	"strings"
+	"time"
 )
 
+/*================================= Clients ==================================*/
+
+// Type Client holds the settings used to make requests to Steam's Web API.
+//
+// The zero value is ready to use: it sends requests via http.DefaultClient to
+// api.steampowered.com and gets any key it needs from GetAPIkey. Programs which
+// need timeouts, custom transports, a different host (such as an httptest
+// server) or several keys at once can make their own Clients.
+//
+type Client struct {
+	// The HTTP client used to send requests; nil means http.DefaultClient.
+	HTTPClient *http.Client
+	// The scheme and host for requests, like "https://api.steampowered.com".
+	// If empty, requests go to api.steampowered.com via HTTP or HTTPS,
+	// depending on whether UseHTTPS is in the flags.
+	BaseURL string
+	// Where to get the Steam API key; nil means GetAPIkey.
+	Key KeySource
+	// Flags to OR into the flags of every call.
+	Flags int
+	// If not nil, counts calls and refuses those which would go over its
+	// daily budget.
+	Quota *Quota
+	// If not nil, how to retry failed calls. If nil, calls with UseRetries
+	// in their flags use DefaultRetryPolicy and others are not retried.
+	RetryPolicy *RetryPolicy
+	// If not nil, called to log each request and its outcome. The URLs it
+	// sees have the key replaced by "REDACTED".
+	Logf func(format string, args ...interface{})
+	// Where to cache results such as the supported-API list; "" means
+	// CacheDirPath().
+	CacheDir string
+	// The list of supported APIs used to check calls with ValidateCalls in
+	// their flags. If nil, such calls use the cached list for the key (or for
+	// no key), fetching it if needed. Set it from LoadSupportedAPIList to
+	// check calls without going online.
+	APIList *SupportedAPIList
+	// The most requests that batched calls (such as GetPlayerSummaries) send
+	// at once; 0 means DefaultMaxConcurrency.
+	MaxConcurrency int
+}
+
+// Type KeySource is a function which returns a Steam API key, or an error.
+// GetAPIkey is a KeySource; StaticKey turns a string into one.
+type KeySource func() (string, error)
+
+// Function StaticKey returns a KeySource which always returns key.
+func StaticKey(key string) KeySource {
+	return func() (string, error) { return key, nil }
+}
+
+// DefaultClient is the Client used by the package-level functions, such as
+// GetJSON.
+var DefaultClient = &Client{}
+
+const defaultHost = "api.steampowered.com"
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+func (c *Client) baseURL(flags int) string {
+	if c.BaseURL != "" {
+		return strings.TrimSuffix(c.BaseURL, "/")
+	} else if flags&UseHTTPS != 0 {
+		return "https://" + defaultHost
+	}
+	return "http://" + defaultHost
+}
+
+func (c *Client) cacheDir() (string, error) {
+	if c.CacheDir != "" {
+		return c.CacheDir, nil
+	}
+	return cacheDirPath()
+}
+
+func (c *Client) apiKey() (string, error) {
+	if c.Key != nil {
+		return c.Key()
+	}
+	return GetAPIkey()
+}
+
 /*=========================== HTTP/HTTPS Requests ============================*/
 
-// only errors come from GetAPIkey ...???
-// panics if odd # of params
+const (
+	// Use https://... instead of http://...
+	UseHTTPS = 1
+	// Pass the Access Key in the request parameters (requires https)
+	UseKey = 3
+	useKey = 2
+	// Retry failures which might be temporary; see RetryPolicy
+	UseRetries = 4
+	// Check calls against the list of supported APIs before sending them
+	ValidateCalls = 8
+)
+
+// Function URLforAPI calls DefaultClient.URLforAPI.
 func URLforAPI(iface, method string, version int, flags int, params ...string) (
 	string, error,
 ) {
-	if len(params)%2 != 0 {
-		panic(fmt.Errorf("Bad call to steamAPI.URLforAPI(): "+
-			"no value for parameter %q", params[len(params)-1]))
+	return DefaultClient.URLforAPI(iface, method, version, flags, params...)
+}
+
+// Function URLforAPIValues calls DefaultClient.URLforAPIValues.
+func URLforAPIValues(iface, method string, version int, flags int,
+	params url.Values,
+) (string, error) {
+	return DefaultClient.URLforAPIValues(iface, method, version, flags, params)
+}
+
+// Function GetResponse calls DefaultClient.GetResponse.
+func GetResponse(
+	what, who string,
+	iface, method string,
+	version int,
+	flags int,
+	params ...string,
+) (*http.Response, error) {
+	return DefaultClient.GetResponse(what, who, iface, method, version, flags,
+		params...)
+}
+
+// Function GetResponseContext calls DefaultClient.GetResponseContext.
+func GetResponseContext(ctx context.Context,
+	what, who string,
+	iface, method string,
+	version int,
+	flags int,
+	params ...string,
+) (*http.Response, error) {
+	return DefaultClient.GetResponseContext(ctx, what, who, iface, method,
+		version, flags, params...)
+}
+
+// Function GetJSON calls DefaultClient.GetJSON.
+func GetJSON(outvar interface{},
+	what, who string,
+	iface, method string,
+	version int,
+	flags int,
+	params ...string,
+) error {
+	return DefaultClient.GetJSON(outvar, what, who, iface, method, version, flags,
+		params...)
+}
+
+// Function GetJSONContext calls DefaultClient.GetJSONContext.
+func GetJSONContext(ctx context.Context, outvar interface{},
+	what, who string,
+	iface, method string,
+	version int,
+	flags int,
+	params ...string,
+) error {
+	return DefaultClient.GetJSONContext(ctx, outvar, what, who, iface, method,
+		version, flags, params...)
+}
+
+// Function GetResponseValues calls DefaultClient.GetResponseValues.
+func GetResponseValues(ctx context.Context,
+	what, who string,
+	iface, method string,
+	version int,
+	flags int,
+	params url.Values,
+) (*http.Response, error) {
+	return DefaultClient.GetResponseValues(ctx, what, who, iface, method,
+		version, flags, params)
+}
+
+// Function GetJSONValues calls DefaultClient.GetJSONValues.
+func GetJSONValues(ctx context.Context, outvar interface{},
+	what, who string,
+	iface, method string,
+	version int,
+	flags int,
+	params url.Values,
+) error {
+	return DefaultClient.GetJSONValues(ctx, outvar, what, who, iface, method,
+		version, flags, params)
+}
+
+// Function GetURL calls DefaultClient.GetURL.
+func GetURL(ctx context.Context, what, who, rawURL string, flags int,
+) (*http.Response, error) {
+	return DefaultClient.GetURL(ctx, what, who, rawURL, flags)
+}
+
+// Function GetURLJSON calls DefaultClient.GetURLJSON.
+func GetURLJSON(ctx context.Context, outvar interface{},
+	what, who, rawURL string, flags int,
+) error {
+	return DefaultClient.GetURLJSON(ctx, outvar, what, who, rawURL, flags)
+}
+
+// Function GetURLPaced calls DefaultClient.GetURLPaced.
+func GetURLPaced(ctx context.Context, what, who, rawURL string, flags int,
+	wait func() error,
+) (*http.Response, error) {
+	return DefaultClient.GetURLPaced(ctx, what, who, rawURL, flags, wait)
+}
+
+// Function GetURLJSONPaced calls DefaultClient.GetURLJSONPaced.
+func GetURLJSONPaced(ctx context.Context, outvar interface{},
+	what, who, rawURL string, flags int, wait func() error,
+) error {
+	return DefaultClient.GetURLJSONPaced(ctx, outvar, what, who, rawURL, flags,
+		wait)
+}
+
+// Method URLforAPI is a wrapper for URLforAPIValues, taking the names and
+// values of the request parameters as alternating elements of params.
+//
+// It returns a *ParamError if params has an odd number of elements.
+//
+func (c *Client) URLforAPI(iface, method string, version int, flags int,
+	params ...string,
+) (string, error) {
+	values, err := pairsToValues(params)
+	if err != nil {
+		return "", err
 	}
-	var err error
+	return c.URLforAPIValues(iface, method, version, flags, values)
+}
+
+// Method URLforAPIValues returns the URL for calling version 'version' of
+// method 'method' of interface 'iface', with params giving the request
+// parameters (see Params for an easy way to make them). Parameter names and
+// values are escaped as needed.
+//
+// The only errors come from getting the key.
+//
+// The URL includes the key (if flags includes UseKey), so use RedactURL before
+// showing it to anyone.
+//
+func (c *Client) URLforAPIValues(iface, method string, version int, flags int,
+	params url.Values,
+) (string, error) {
+	flags |= c.Flags
 	buf := new(strings.Builder)
-	if flags&UseHTTPS != 0 {
-		fmt.Fprintf(buf, "https")
-	} else {
-		fmt.Fprintf(buf, "http")
-	}
-	fmt.Fprintf(buf, "http://api.steampowered.com/%s/%s/v%d/",
-		iface, method, version)
-	sep := '?'
+	fmt.Fprintf(buf, "%s/%s/%s/v%d/", c.baseURL(flags),
+		url.PathEscape(iface), url.PathEscape(method), version)
+	sep := "?"
 	if flags&useKey != 0 {
-		sep = '&'
-		ak, err := GetAPIkey()
+		sep = "&"
+		ak, err := c.apiKey()
 		if err != nil {
 			return "", err
 		}
-		fmt.Fprintf(buf, "?key=%s", ak)
+		fmt.Fprintf(buf, "?key=%s", url.QueryEscape(ak))
 	}
-	for i := 0; err == nil && i < len(params); i += 2 {
-		fmt.Fprintf(buf, "%c%s=%s", sep, params[i], params[i+1])
-		sep = '&'
+	if len(params) > 0 {
+		fmt.Fprintf(buf, "%s%s", sep, params.Encode())
 	}
 	return buf.String(), nil
 }
 
-const (
-	// Use https://... instead of http://...
-	UseHTTPS = 1
-	// Pass the Access Key in the request parameters (requires https)
-	UseKey = 3
-	useKey = 2
-)
+// Method GetResponse calls GetResponseContext with context.Background().
+func (c *Client) GetResponse(
+	what, who string,
+	iface, method string,
+	version int,
+	flags int,
+	params ...string,
+) (*http.Response, error) {
+	return c.GetResponseContext(context.Background(), what, who, iface, method,
+		version, flags, params...)
+}
 
-func GetResponse(
+// Method GetResponseContext is a wrapper for GetResponseValues, taking the
+// names and values of the request parameters as alternating elements of params.
+func (c *Client) GetResponseContext(ctx context.Context,
 	what, who string,
 	iface, method string,
 	version int,
 	flags int,
 	params ...string,
 ) (*http.Response, error) {
-	url, err := URLforAPI(iface, method, version, flags, params...)
+	values, err := pairsToValues(params)
 	if err != nil {
 		return nil, err
 	}
-	response, err := http.Get(url)
+	return c.GetResponseValues(ctx, what, who, iface, method, version, flags,
+		values)
+}
+
+// Method GetResponseValues sends a GET request for the URL from
+// c.URLforAPIValues and returns the response, or returns a *WebError. The
+// caller must close the response body.
+//
+// Responses with a status code outside 200-299 count as failures: the
+// WebError records the status, the headers and the start of the body, and
+// matches one of ErrUnauthorized, ErrNotFound, ErrRateLimited or
+// ErrServerError where appropriate.
+//
+// If ctx is cancelled or times out before the response arrives, the request is
+// abandoned and the WebError wraps ctx.Err().
+//
+// If c.Quota is not nil, GetResponseValues counts the call against the key
+// used (or against "no key"), and returns a *QuotaError (which matches
+// ErrQuotaExhausted) without sending anything if the budget is used up.
+//
+// If the call has a retry policy (see Client.RetryPolicy), transport errors
+// and responses with status 429, 500, 502, 503 or 504 are retried; each attempt
+// counts against the quota. When the retries run out, the WebError records the
+// number of attempts and the last status code.
+//
+// If flags (or c.Flags) include ValidateCalls, GetResponseValues first checks
+// the call against a list of supported APIs (see Client.APIList), and returns
+// a *ValidationError without sending anything if the call does not match.
+//
+// Arguments 'what' and 'who' describe the request for error messages; see
+// WebError.
+//
+func (c *Client) GetResponseValues(ctx context.Context,
+	what, who string,
+	iface, method string,
+	version int,
+	flags int,
+	params url.Values,
+) (*http.Response, error) {
+	requestURL, err := c.URLforAPIValues(iface, method, version, flags, params)
 	if err != nil {
-		if response != nil {
-			response.Body.Close()
+		return nil, err
+	}
+	if (flags|c.Flags)&ValidateCalls != 0 {
+		err = c.validateCall(ctx, iface, method, version, flags|c.Flags, params)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var spend func() error
+	if c.Quota != nil {
+		key := ""
+		if (flags|c.Flags)&useKey != 0 {
+			key, _ = c.apiKey() // URLforAPI succeeded, so this will too
 		}
-		return nil, &WebError{Action: "get",
-			What: what, Who: who, URL: url, BaseError: err}
+		spend = func() error { return c.Quota.Spend(key, iface, method) }
 	}
-	return response, nil
+	return c.getWithRetries(ctx, what, who, requestURL, c.retryPolicy(flags),
+		spend)
 }
 
-func GetJSON(outvar interface{},
+// getWithRetries does the work of GetResponseValues and GetURL: it sends GET
+// requests for requestURL as 'policy' allows, calling spend (unless it is nil)
+// before each one, until one succeeds or there is no point in trying again.
+func (c *Client) getWithRetries(ctx context.Context, what, who, requestURL string,
+	policy *RetryPolicy, spend func() error,
+) (*http.Response, error) {
+	// Only the request itself gets to see the key.
+	shownURL := RedactURL(requestURL)
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		if spend != nil {
+			if err := spend(); err != nil {
+				return nil, err
+			}
+		}
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL,
+			nil)
+		if err != nil {
+			redactURLError(err)
+			return nil, &WebError{Action: "build request for",
+				What: what, Who: who, URL: shownURL, BaseError: err}
+		}
+		response, err := c.httpClient().Do(request)
+		if err != nil {
+			redactURLError(err)
+			c.logf("GET %s (attempt %d): %s", shownURL, attempt, err)
+		} else {
+			c.logf("GET %s (attempt %d): %s", shownURL, attempt, response.Status)
+			if !isHTTPerror(response.StatusCode) {
+				return response, nil
+			}
+		}
+
+		webErr := &WebError{Action: "get", What: what, Who: who, URL: shownURL,
+			Attempts: attempt}
+		if err != nil {
+			if response != nil {
+				response.Body.Close()
+			}
+			webErr.BaseError = ctxError(ctx, err)
+		} else {
+			webErr.setStatus(response) // Closes response.Body
+		}
+		if policy == nil || ctx.Err() != nil || attempt >= policy.MaxAttempts ||
+			(err == nil && !isRetryableStatus(response.StatusCode)) {
+			return nil, webErr
+		}
+		pause := policy.delay(attempt, response)
+		if policy.MaxElapsed > 0 && time.Since(start)+pause > policy.MaxElapsed {
+			return nil, webErr
+		}
+		err = sleepContext(ctx, pause)
+		if err != nil {
+			webErr.BaseError = err
+			return nil, webErr
+		}
+	}
+}
+
+// Method GetJSON calls GetJSONContext with context.Background().
+func (c *Client) GetJSON(outvar interface{},
 	what, who string,
 	iface, method string,
 	version int,
 	flags int,
 	params ...string,
 ) error {
-	response, err := GetResponse(what, who, iface, method, version, flags, params...)
-	url, err := URLforAPI(iface, method, version, flags, params...)
+	return c.GetJSONContext(context.Background(), outvar, what, who,
+		iface, method, version, flags, params...)
+}
+
+// Method GetJSONContext is a wrapper for GetJSONValues, taking the names and
+// values of the request parameters as alternating elements of params.
+func (c *Client) GetJSONContext(ctx context.Context, outvar interface{},
+	what, who string,
+	iface, method string,
+	version int,
+	flags int,
+	params ...string,
+) error {
+	values, err := pairsToValues(params)
+	if err != nil {
+		return err
+	}
+	return c.GetJSONValues(ctx, outvar, what, who, iface, method, version, flags,
+		values)
+}
+
+// Method GetJSONValues is like GetResponseValues, but decodes the body of the
+// response as JSON into outvar.
+func (c *Client) GetJSONValues(ctx context.Context, outvar interface{},
+	what, who string,
+	iface, method string,
+	version int,
+	flags int,
+	params url.Values,
+) error {
+	response, err := c.GetResponseValues(ctx, what, who, iface, method,
+		version, flags, params)
 	if err != nil {
 		return err
 	}
+	return decodeJSONResponse(ctx, response, outvar, what, who)
+}
+
+// Method GetURL sends a GET request for rawURL, which need not be a Web API
+// URL (it might be one for Steam's unofficial store API, for example), and
+// returns the response or a *WebError, like GetResponseValues.
+//
+// Since such URLs are not Web API calls, GetURL ignores c.Quota and c.APIList,
+// and the only flag which matters is UseRetries. The caller must close the
+// response body.
+//
+func (c *Client) GetURL(ctx context.Context, what, who, rawURL string, flags int,
+) (*http.Response, error) {
+	return c.GetURLPaced(ctx, what, who, rawURL, flags, nil)
+}
+
+// Method GetURLPaced is like GetURL, but calls wait (unless it is nil) before
+// each attempt, including retries, and gives up with wait's error if it fails.
+// Callers with rate limits of their own, such as package Storefront, use wait
+// to keep to them.
+//
+func (c *Client) GetURLPaced(ctx context.Context, what, who, rawURL string,
+	flags int, wait func() error,
+) (*http.Response, error) {
+	return c.getWithRetries(ctx, what, who, rawURL, c.retryPolicy(flags), wait)
+}
+
+// Method GetURLJSON is like GetURL, but decodes the body of the response as
+// JSON into outvar.
+func (c *Client) GetURLJSON(ctx context.Context, outvar interface{},
+	what, who, rawURL string, flags int,
+) error {
+	return c.GetURLJSONPaced(ctx, outvar, what, who, rawURL, flags, nil)
+}
+
+// Method GetURLJSONPaced is like GetURLPaced, but decodes the body of the
+// response as JSON into outvar.
+func (c *Client) GetURLJSONPaced(ctx context.Context, outvar interface{},
+	what, who, rawURL string, flags int, wait func() error,
+) error {
+	response, err := c.GetURLPaced(ctx, what, who, rawURL, flags, wait)
 	if err != nil {
-		if response != nil {
-			response.Body.Close()
-		}
-		return &WebError{Action: "get",
-			What: what, Who: who, URL: url, BaseError: err}
+		return err
 	}
+	return decodeJSONResponse(ctx, response, outvar, what, who)
+}
+
+// decodeJSONResponse decodes the body of response into outvar, then closes it.
+func decodeJSONResponse(ctx context.Context, response *http.Response,
+	outvar interface{}, what, who string,
+) error {
 	defer response.Body.Close()
 	//
 	d := json.NewDecoder(response.Body)
-	err = d.Decode(outvar)
+	err := d.Decode(outvar)
 	if err != nil {
 		return &WebError{Action: "decode",
-			What: what, Who: who, URL: url, BaseError: err}
+			What: what, Who: who, URL: RedactURL(response.Request.URL.String()),
+			BaseError: ctxError(ctx, err)}
 	}
 	return nil
 }
 
+func (c *Client) logf(format string, args ...interface{}) {
+	if c.Logf != nil {
+		c.Logf(format, args...)
+	}
+}
+
+var regexpKeyParam = regexp.MustCompile(`([?&]key=)[^&#]*`)
+
+// Function RedactURL returns rawURL with the value of any "key" parameter
+// replaced by "REDACTED", so that the result is safe to log or show to users.
+func RedactURL(rawURL string) string {
+	return regexpKeyParam.ReplaceAllString(rawURL, "${1}REDACTED")
+}
+
+// redactURLError removes any key from the URL in err, if err is a *url.Error
+// (as returned by http.Client.Do), since its Error() method shows the URL.
+func redactURLError(err error) {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = RedactURL(urlErr.URL)
+	}
+}
+
+func isHTTPerror(code int) bool {
+	return code/100 != 2
+}
+
+// ctxError returns ctx.Err() if ctx is done, otherwise err. This stops errors
+// like "read: connection reset" hiding the fact that the caller gave up.
+func ctxError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return err
+}
+
 /*============================ Utility Functions =============================*/
 
+// These values match any *WebError with a suitable HTTP status code, when used
+// with errors.Is.
+var (
+	// Status 401 or 403: usually a bad or missing key
+	ErrUnauthorized = errors.New("not authorized by Steam")
+	// Status 404: usually a misspelt interface or method, or a bad version
+	ErrNotFound = errors.New("not found by Steam")
+	// Status 429: too many requests
+	ErrRateLimited = errors.New("rate-limited by Steam")
+	// Status 500-599
+	ErrServerError = errors.New("Steam server error")
+)
+
+// Type WebError represents a failure to get (or make sense of) a response from
+// Steam's Web API.
+//
+// Fields What and Who, if not empty, describe the request in words (such as
+// "player summaries" and "user 76561197960287930"); otherwise Error() uses the
+// URL.
+//
 type WebError struct {
-	Action    string
-	What      string
-	Who       string
-	BaseError error
-	URL       string
+	Action     string      // What we were trying to do ("get", "decode", etc)
+	What       string      // What we were trying to get
+	Who        string      // Whose data we were trying to get, or ""
+	BaseError  error       // The lower-level error, if any
+	URL        string      // The URL of the request
+	StatusCode int         // The HTTP status code of the last response, or 0
+	StatusText string      // The HTTP status line of the last response, or ""
+	Excerpt    string      // The start of the body of that response, or ""
+	Header     http.Header // The headers of that response, or nil
+	Attempts   int         // How many times the request was sent, if more than 1
 }
 
+// maxExcerpt is how many bytes of an error response WebError keeps.
+const maxExcerpt = 200
+
+// setStatus records the status, headers and start of the body of a failed
+// response, then closes the body.
+func (e *WebError) setStatus(response *http.Response) {
+	e.StatusCode, e.StatusText = response.StatusCode, response.Status
+	e.Header = response.Header
+	start, _ := ioutil.ReadAll(io.LimitReader(response.Body, maxExcerpt))
+	response.Body.Close()
+	e.Excerpt = strings.TrimSpace(strings.ToValidUTF8(string(start), "�"))
+}
 
 func (e *WebError) Unwrap() error { return e.BaseError }
 
+// Method Is lets errors.Is match a WebError against ErrUnauthorized,
+// ErrNotFound, ErrRateLimited and ErrServerError.
+func (e *WebError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized ||
+			e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServerError:
+		return e.StatusCode >= 500 && e.StatusCode <= 599
+	}
+	return false
+}
+
 func (e *WebError) Error() string {
 	source := e.URL
 	if e.What != "" {
-		source := e.What
+		source = e.What
 		if e.Who != "" {
 			source += " for " + e.Who
 		}
 	}
-	return fmt.Sprintf("cannot %s %s: %s", e.Action, source, e.BaseError)
+	problem := ""
+	if e.BaseError != nil {
+		problem = e.BaseError.Error()
+	} else {
+		problem = fmt.Sprintf("HTTP status %s", e.StatusText)
+		if e.Excerpt != "" {
+			problem += fmt.Sprintf(" %q", e.Excerpt)
+		}
+	}
+	if e.Attempts > 1 {
+		problem += fmt.Sprintf(" (after %d attempts)", e.Attempts)
+	}
+	return fmt.Sprintf("cannot %s %s: %s", e.Action, source, problem)
 }
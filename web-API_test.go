@@ -0,0 +1,106 @@
+package SteamAPI
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const testKey = "0123456789ABCDEF"
+
+func TestRedactURL(t *testing.T) {
+	for _, tc := range []struct{ in, want string }{
+		{"https://api.steampowered.com/I/M/v1/?key=SECRET",
+			"https://api.steampowered.com/I/M/v1/?key=REDACTED"},
+		{"https://h/I/M/v1/?key=SECRET&steamid=1#frag",
+			"https://h/I/M/v1/?key=REDACTED&steamid=1#frag"},
+		{"https://h/I/M/v1/?steamid=1&key=SECRET",
+			"https://h/I/M/v1/?steamid=1&key=REDACTED"},
+		{"https://h/I/M/v1/?monkey=SECRET", "https://h/I/M/v1/?monkey=SECRET"},
+		{"https://h/I/M/v1/?steamid=1", "https://h/I/M/v1/?steamid=1"},
+	} {
+		if got := RedactURL(tc.in); got != tc.want {
+			t.Errorf("RedactURL(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+// echoServer is a stand-in for the Web API which answers every request with
+// status, echoing the query as {"key":...,"steamid":...} if status is 200.
+type echoServer struct {
+	status int
+	paths  []string
+}
+
+func (es *echoServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	es.paths = append(es.paths, r.URL.Path)
+	if es.status != http.StatusOK {
+		http.Error(w, "no such luck", es.status)
+		return
+	}
+	q := r.URL.Query()
+	fmt.Fprintf(w, `{"key":%q,"steamid":%q}`, q.Get("key"), q.Get("steamid"))
+}
+
+func TestClientSendsKeyButNeverShowsIt(t *testing.T) {
+	es := &echoServer{status: http.StatusOK}
+	server := httptest.NewServer(es)
+	defer server.Close()
+	var logged []string
+	c := &Client{BaseURL: server.URL + "/", Key: StaticKey(testKey),
+		Logf: func(format string, args ...interface{}) {
+			logged = append(logged, fmt.Sprintf(format, args...))
+		}}
+
+	var echo struct{ Key, SteamID string }
+	err := c.GetJSONContext(context.Background(), &echo, "echo", "",
+		"ITest", "Echo", 2, UseKey, "steamid", "76561197960287930")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if echo.Key != testKey || echo.SteamID != "76561197960287930" {
+		t.Errorf("server saw key %q, steamid %q; want %q, 76561197960287930",
+			echo.Key, echo.SteamID, testKey)
+	}
+	if len(es.paths) != 1 || es.paths[0] != "/ITest/Echo/v2/" {
+		t.Errorf("server saw paths %q, want [/ITest/Echo/v2/]", es.paths)
+	}
+	if len(logged) != 1 || strings.Contains(logged[0], testKey) ||
+		!strings.Contains(logged[0], "key=REDACTED") {
+		t.Errorf("logged %q, want one line with the key redacted", logged)
+	}
+
+	es.status = http.StatusForbidden
+	err = c.GetJSONContext(context.Background(), &echo, "echo", "",
+		"ITest", "Echo", 2, UseKey)
+	var webErr *WebError
+	if !errors.As(err, &webErr) || !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("after a 403: got %v, want a *WebError matching ErrUnauthorized",
+			err)
+	}
+	if strings.Contains(err.Error(), testKey) || strings.Contains(webErr.URL, testKey) {
+		t.Errorf("error %q (URL %q) shows the key", err, webErr.URL)
+	}
+	if webErr.StatusCode != http.StatusForbidden || webErr.Excerpt != "no such luck" {
+		t.Errorf("StatusCode = %d, Excerpt = %q; want 403, %q",
+			webErr.StatusCode, webErr.Excerpt, "no such luck")
+	}
+}
+
+func TestClientTransportErrorHidesKey(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close() // Nothing is listening now.
+	c := &Client{BaseURL: server.URL, Key: StaticKey(testKey)}
+
+	_, err := c.GetResponse("echo", "", "ITest", "Echo", 1, UseKey)
+	if err == nil {
+		t.Fatal("got no error from a closed server")
+	}
+	if strings.Contains(err.Error(), testKey) {
+		t.Errorf("error %q shows the key", err)
+	}
+}